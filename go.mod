@@ -1,3 +1,3 @@
 module github.com/yahoojapan/k2hash_go
 
-go 1.15
+go 1.23
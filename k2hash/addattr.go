@@ -25,45 +25,38 @@ import (
 )
 
 import (
-	"fmt"
 	"unsafe"
 )
 
 // AddAttr adds an attribute with a value to a key.
 func (k2h *K2hash) AddAttr(k interface{}, ak interface{}, av interface{}, options ...func(*Params)) (bool, error) {
 	// 1. binary or text
-	var key string
-	var attrkey string
-	var attrval string
-	switch k.(type) {
-	default:
-		return false, fmt.Errorf("unsupported key data format %T", k)
-	case string:
-		key = k.(string)
+	key, err := toBytes(k)
+	if err != nil {
+		return false, err
 	}
-	switch ak.(type) {
-	default:
-		return false, fmt.Errorf("unsupported key data format %T", ak)
-	case string:
-		attrkey = ak.(string)
+	attrkey, err := toBytes(ak)
+	if err != nil {
+		return false, err
 	}
-	switch av.(type) {
-	default:
-		return false, fmt.Errorf("unsupported key data format %T", av)
-	case string:
-		attrval = av.(string)
+	attrval, err := toBytes(av)
+	if err != nil {
+		return false, err
+	}
+	if err := k2h.checkWritable("AddAttr", key); err != nil {
+		return false, err
 	}
 
-	// 2. get from k2hash get API
-	cKey := C.CString(key)
+	// 2. add to k2hash add attribute API
+	cKey, cKeyLen := cBytes(key)
 	defer C.free(unsafe.Pointer(cKey))
-	cAttrKey := C.CString(attrkey)
+	cAttrKey, cAttrKeyLen := cBytes(attrkey)
 	defer C.free(unsafe.Pointer(cAttrKey))
-	cAttrVal := C.CString(attrval)
+	cAttrVal, cAttrValLen := cBytes(attrval)
 	defer C.free(unsafe.Pointer(cAttrVal))
-	ok := C.k2h_add_str_attr(k2h.handle, (*C.char)(cKey), (*C.char)(cAttrKey), (*C.char)(cAttrVal))
+	ok, errno := C.k2h_add_attr(k2h.handle, cKey, cKeyLen, cAttrKey, cAttrKeyLen, cAttrVal, cAttrValLen)
 	if ok != true {
-		return false, fmt.Errorf("C.k2h_add_str_attr return false")
+		return false, k2h.keyError("AddAttr", key, errno)
 	}
 	return true, nil
 }
@@ -25,33 +25,26 @@ import (
 )
 
 import (
-	"fmt"
 	"unsafe"
 )
 
 // AddSubKey add a subkey to a key.
 func (k2h *K2hash) AddSubKey(k interface{}, s interface{}, v interface{}, options ...func(*Params)) (bool, error) {
 	// 1. binary or text
-	var key string
-	var subkey string
-	var val string
-	switch k.(type) {
-	default:
-		return false, fmt.Errorf("unsupported key data format %T", k)
-	case string:
-		key = k.(string)
+	key, err := toBytes(k)
+	if err != nil {
+		return false, err
 	}
-	switch s.(type) {
-	default:
-		return false, fmt.Errorf("unsupported key data format %T", s)
-	case string:
-		subkey = s.(string)
+	subkey, err := toBytes(s)
+	if err != nil {
+		return false, err
 	}
-	switch v.(type) {
-	default:
-		return false, fmt.Errorf("unsupported key data format %T", v)
-	case string:
-		val = v.(string)
+	val, err := toBytes(v)
+	if err != nil {
+		return false, err
+	}
+	if err := k2h.checkWritable("AddSubKey", key); err != nil {
+		return false, err
 	}
 
 	// 2. set params
@@ -63,13 +56,16 @@ func (k2h *K2hash) AddSubKey(k interface{}, s interface{}, v interface{}, option
 	for _, option := range options {
 		option(&params)
 	}
+	if err := params.validate(); err != nil {
+		return false, err
+	}
 
-	// 3. get from k2hash get API
-	cKey := C.CString(key)
+	// 3. add to k2hash add subkey API
+	cKey, cKeyLen := cBytes(key)
 	defer C.free(unsafe.Pointer(cKey))
-	cSubKey := C.CString(subkey)
+	cSubKey, cSubKeyLen := cBytes(subkey)
 	defer C.free(unsafe.Pointer(cSubKey))
-	cVal := C.CString(val)
+	cVal, cValLen := cBytes(val)
 	defer C.free(unsafe.Pointer(cVal))
 	cPass := C.CString(params.password)
 	defer C.free(unsafe.Pointer(cPass))
@@ -78,9 +74,9 @@ func (k2h *K2hash) AddSubKey(k interface{}, s interface{}, v interface{}, option
 	if params.expirationDuration != 0 {
 		expire = (*C.time_t)(&params.expirationDuration)
 	}
-	ok := C.k2h_add_str_subkey_wa(k2h.handle, (*C.char)(cKey), (*C.char)(cSubKey), (*C.char)(cVal), cPass, expire)
+	ok, errno := C.k2h_add_subkey_wa(k2h.handle, cKey, cKeyLen, cSubKey, cSubKeyLen, cVal, cValLen, cPass, expire)
 	if ok != true {
-		return false, fmt.Errorf("C.C.k2h_add_str_subkey_wa return false")
+		return false, newOpError("AddSubKey", key, errno, nil)
 	}
 	return true, nil
 }
@@ -0,0 +1,219 @@
+//
+// k2hash_go
+//
+// Copyright 2018 Yahoo Japan Corporation.
+//
+// Go driver for k2hash that is a NoSQL Key Value Store(KVS) library.
+// For k2hash, see https://github.com/yahoojapan/k2hash for the details.
+//
+// For the full copyright and license information, please view
+// the license file that was distributed with this source code.
+//
+// AUTHOR:   Hirotaka Wakabayashi
+// CREATE:   Sun, 18 Oct 2026
+// REVISION:
+//
+
+package k2hash
+
+import (
+	// #cgo CFLAGS: -g -O2 -Wall -Wextra -Wno-unused-variable -Wno-unused-parameter -I. -I/usr/include/k2hash
+	// #cgo LDFLAGS: -L/usr/lib -lk2hash
+	// #include <stdlib.h>
+	// #include "k2hash.h"
+	"C"
+)
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+	"unsafe"
+)
+
+// GetAttr returns the value of an attribute of a key as it is saved. The name is either a string or a []byte,
+// and it matches the attribute name regardless of the null termination.
+// It returns ErrNotFound if the key or the attribute doesn't exist.
+func (k2h *K2hash) GetAttr(k interface{}, n interface{}) ([]byte, error) {
+	// 1. binary or text
+	key, err := toBytes(k)
+	if err != nil {
+		return nil, err
+	}
+	if err := k2h.checkOpen("GetAttr", key); err != nil {
+		return nil, err
+	}
+	_, val, err := k2h.findAttr("GetAttr", key, n)
+	if err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+// SetAttr sets an attribute of a key. An existing attribute with the same name is replaced regardless of
+// the null termination. The name and the value are either a string or a []byte. A string is saved with
+// a null termination. The key is rewritten in the same way as RemoveAttr, so that an encrypted value needs
+// WithPassword. The builtin attributes can't be set by SetAttr. Use ExpireAt for the expiration time.
+func (k2h *K2hash) SetAttr(k interface{}, n interface{}, v interface{}, options ...func(*Params)) (bool, error) {
+	// 1. binary or text
+	key, err := toBytes(k)
+	if err != nil {
+		return false, err
+	}
+	val, err := toBytes(v)
+	if err != nil {
+		return false, err
+	}
+	name, err := toBytes(n)
+	if err != nil {
+		return false, err
+	}
+	if err := k2h.checkWritable("SetAttr", key); err != nil {
+		return false, err
+	}
+	if isBuiltinAttr(string(trimNull(name))) {
+		return false, fmt.Errorf("%w: the builtin attribute %q can't be set", ErrInvalid, trimNull(name))
+	}
+
+	// 2. use the name as it is saved if the attribute exists
+	saved, _, err := k2h.findAttr("SetAttr", key, n)
+	if err == nil {
+		name = saved
+	} else if !errors.Is(err, ErrNotFound) || !k2h.exists(key) {
+		return false, err
+	}
+
+	// 3. rewrite the key with the attribute
+	return k2h.rewriteAttrs("SetAttr", key, name, val, options)
+}
+
+// RemoveAttr removes an attribute of a key. It returns ErrNotFound if the key or the attribute doesn't exist.
+// libk2hash can't remove an attribute in place, so that the value, the subkeys and the other attributes
+// are written again. The key is replaced at once through a staging key, so that it is left as it is
+// if an error occurs or the process crashes. The rewrite updates mtime and history like other writes, and
+// the expiration time is kept. An encrypted value needs WithPassword to be encrypted again.
+func (k2h *K2hash) RemoveAttr(k interface{}, n interface{}, options ...func(*Params)) (bool, error) {
+	// 1. binary or text
+	key, err := toBytes(k)
+	if err != nil {
+		return false, err
+	}
+	if err := k2h.checkWritable("RemoveAttr", key); err != nil {
+		return false, err
+	}
+
+	// 2. find the attribute name as it is saved
+	name, _, err := k2h.findAttr("RemoveAttr", key, n)
+	if err != nil {
+		return false, err
+	}
+	return k2h.rewriteAttrs("RemoveAttr", key, name, nil, options)
+}
+
+// rewriteAttrs rewrites a key without the attributes with the name, and adds the attribute with val
+// unless val is nil. The expire attribute is written as the expiration time of the rewrite. The key is replaced through the staging key in the same way as SetEntry, so that it is
+// left as it is if an error occurs. The builtin attributes are left to the builtin attribute plugin.
+func (k2h *K2hash) rewriteAttrs(op string, key []byte, name []byte, val []byte, options []func(*Params)) (bool, error) {
+	// 1. set params
+	params := Params{
+		password:           "",
+		expirationDuration: 0,
+		replaceAttrs:       true,
+	}
+	for _, option := range options {
+		option(&params)
+	}
+	defer k2h.entries.lock(key)()
+
+	// 2. attributes to write again
+	names, vals, err := k2h.attrPack(op, key)
+	if err != nil {
+		return false, err
+	}
+	var newNames, newVals [][]byte
+	var expire time.Time
+	for i := range names {
+		n := string(trimNull(names[i]))
+		switch {
+		case n == attrEncrypt && params.password == "":
+			return false, newOpError(op, key, nil, fmt.Errorf("%w: an encrypted value needs a password to be written again", ErrInvalid))
+		case n == string(trimNull(name)):
+			continue
+		case n == attrExpire:
+			expire = decodeAttrTime(vals[i])
+		case !isBuiltinAttr(n):
+			newNames = append(newNames, names[i])
+			newVals = append(newVals, vals[i])
+		}
+	}
+	switch {
+	case val == nil:
+	case string(trimNull(name)) == attrExpire:
+		expire = decodeAttrTime(val)
+	default:
+		newNames = append(newNames, name)
+		newVals = append(newVals, val)
+	}
+
+	// 3. keep or set the expiration time
+	if !expire.IsZero() {
+		d := time.Until(expire)
+		if d <= 0 {
+			return false, newOpError(op, key, nil, ErrNotFound)
+		}
+		params.expirationDuration = int64((d + time.Second - 1) / time.Second)
+	}
+
+	// 4. rewrite the key with the value decrypted by the password
+	e, err := k2h.GetEntry(key, WithPassword(params.password))
+	if err != nil {
+		return false, err
+	}
+	return k2h.writeEntry(op, key, e.Value, e.SubKeys, newNames, newVals, params)
+}
+
+// attrPack returns the names and the values of the attributes of a key as they are saved.
+func (k2h *K2hash) attrPack(op string, key []byte) ([][]byte, [][]byte, error) {
+	cKey, cKeyLen := cBytes(key)
+	defer C.free(unsafe.Pointer(cKey))
+	var attrpack C.PK2HATTRPCK
+	var attrpackCnt C.int
+	ok, errno := C.k2h_get_attrs(k2h.handle, cKey, cKeyLen, &attrpack, &attrpackCnt)
+	defer C.k2h_free_attrpack(attrpack, attrpackCnt)
+	if ok != true {
+		if k2h.exists(key) {
+			// a key without attributes
+			return nil, nil, nil
+		}
+		return nil, nil, newOpError(op, key, errno, ErrNotFound)
+	}
+	names, vals := goAttrPack(attrpack, attrpackCnt)
+	return names, vals, nil
+}
+
+// findAttr returns the name and the value of an attribute as they are saved.
+func (k2h *K2hash) findAttr(op string, key []byte, n interface{}) ([]byte, []byte, error) {
+	name, err := toBytes(n)
+	if err != nil {
+		return nil, nil, err
+	}
+	names, vals, err := k2h.attrPack(op, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range names {
+		if bytes.Equal(trimNull(names[i]), trimNull(name)) {
+			return names[i], vals[i], nil
+		}
+	}
+	return nil, nil, newOpError(op, key, nil, ErrNotFound)
+}
+
+// Local Variables:
+// c-basic-offset: 4
+// tab-width: 4
+// indent-tabs-mode: t
+// End:
+// vim600: noexpandtab sw=4 ts=4 fdm=marker
+// vim<600: noexpandtab sw=4 ts=4
@@ -25,81 +25,102 @@ import (
 )
 
 import (
-	"fmt"
 	"unsafe"
 )
 
 // EnableMtime enables the k2hash file attributes of modification time.
 func (k2h *K2hash) EnableMtime(enable bool) (bool, error) {
+	if err := k2h.checkOpen("EnableMtime", nil); err != nil {
+		return false, err
+	}
 	cBool := C._Bool(enable)
-	ok := C.k2h_set_common_attr(k2h.handle, (*C._Bool)(&cBool), nil, nil, nil, nil)
+	ok, errno := C.k2h_set_common_attr(k2h.handle, (*C._Bool)(&cBool), nil, nil, nil, nil)
 	if ok != true {
-		return false, fmt.Errorf("k2h_set_common_attr() returns false")
+		return false, newOpError("EnableMtime", nil, errno, nil)
 	}
 	return true, nil
 }
 
 // EnableEncryption enables the k2hash file attributes of data encryption.
 func (k2h *K2hash) EnableEncryption(enable bool, file string) (bool, error) {
+	if err := k2h.checkOpen("EnableEncryption", nil); err != nil {
+		return false, err
+	}
 	cFile := C.CString(file)
 	defer C.free(unsafe.Pointer(cFile))
 	cBool := C._Bool(enable)
-	ok := C.k2h_set_common_attr(k2h.handle, nil, (*C._Bool)(&cBool), cFile, nil, nil)
+	ok, errno := C.k2h_set_common_attr(k2h.handle, nil, (*C._Bool)(&cBool), cFile, nil, nil)
 	if ok != true {
-		return false, fmt.Errorf("k2h_set_common_attr() returns false")
+		return false, newOpError("EnableEncryption", nil, errno, nil)
 	}
 	return true, nil
 }
 
 // EnableHistory enables the k2hash file attributes of history.
 func (k2h *K2hash) EnableHistory(enable bool) (bool, error) {
+	if err := k2h.checkOpen("EnableHistory", nil); err != nil {
+		return false, err
+	}
 	cBool := C._Bool(enable)
-	ok := C.k2h_set_common_attr(k2h.handle, nil, nil, nil, (*C._Bool)(&cBool), nil)
+	ok, errno := C.k2h_set_common_attr(k2h.handle, nil, nil, nil, (*C._Bool)(&cBool), nil)
 	if ok != true {
-		return false, fmt.Errorf("k2h_set_common_attr() returns false")
+		return false, newOpError("EnableHistory", nil, errno, nil)
 	}
 	return true, nil
 }
 
 // SetExpirationDuration enables the k2hash file attributes of modification time.
 func (k2h *K2hash) SetExpirationDuration(duration int) (bool, error) {
+	if err := k2h.checkOpen("SetExpirationDuration", nil); err != nil {
+		return false, err
+	}
 	cDuration := C.time_t(duration)
-	ok := C.k2h_set_common_attr(k2h.handle, nil, nil, nil, nil, (*C.time_t)(&cDuration))
+	ok, errno := C.k2h_set_common_attr(k2h.handle, nil, nil, nil, nil, (*C.time_t)(&cDuration))
 	if ok != true {
-		return false, fmt.Errorf("k2h_set_common_attr() returns false")
+		return false, newOpError("SetExpirationDuration", nil, errno, nil)
 	}
+	k2h.defaultExpiration.Store(int64(duration))
 	return true, nil
 }
 
 // AddAttrPluginLibrary loads a shared library for processing attribute data.
 func (k2h *K2hash) AddAttrPluginLibrary(file string) (bool, error) {
+	if err := k2h.checkOpen("AddAttrPluginLibrary", nil); err != nil {
+		return false, err
+	}
 	cFile := C.CString(file)
 	defer C.free(unsafe.Pointer(cFile))
-	ok := C.k2h_add_attr_plugin_library(k2h.handle, cFile)
+	ok, errno := C.k2h_add_attr_plugin_library(k2h.handle, cFile)
 	if ok != true {
-		return false, fmt.Errorf("k2h_add_attr_plugin_library() returns false")
+		return false, newOpError("AddAttrPluginLibrary", nil, errno, nil)
 	}
 	return true, nil
 }
 
 // AddDecryptionPassword sets the decryption passphrase.
 func (k2h *K2hash) AddDecryptionPassword(pass string) (bool, error) {
+	if err := k2h.checkOpen("AddDecryptionPassword", nil); err != nil {
+		return false, err
+	}
 	cPass := C.CString(pass)
 	defer C.free(unsafe.Pointer(cPass))
-	ok := C.k2h_add_attr_crypt_pass(k2h.handle, cPass, false)
+	ok, errno := C.k2h_add_attr_crypt_pass(k2h.handle, cPass, false)
 	if ok != true {
-		return false, fmt.Errorf("k2h_add_attr_crypt_pass() returns false")
+		return false, newOpError("AddDecryptionPassword", nil, errno, nil)
 	}
 	return true, nil
 }
 
 // SetDefaultEncryptionPassword sets the encryption passphrase.
 func (k2h *K2hash) SetDefaultEncryptionPassword(pass string) (bool, error) {
+	if err := k2h.checkOpen("SetDefaultEncryptionPassword", nil); err != nil {
+		return false, err
+	}
 	cPass := C.CString(pass)
 	defer C.free(unsafe.Pointer(cPass))
-	ok := C.k2h_add_attr_crypt_pass(k2h.handle, cPass, true)
+	ok, errno := C.k2h_add_attr_crypt_pass(k2h.handle, cPass, true)
 	if ok != true {
-		return false, fmt.Errorf("k2h_add_attr_crypt_pass() returns false")
+		return false, newOpError("SetDefaultEncryptionPassword", nil, errno, nil)
 	}
 	return true, nil
 }
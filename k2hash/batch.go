@@ -0,0 +1,342 @@
+//
+// k2hash_go
+//
+// Copyright 2018 Yahoo Japan Corporation.
+//
+// Go driver for k2hash that is a NoSQL Key Value Store(KVS) library.
+// For k2hash, see https://github.com/yahoojapan/k2hash for the details.
+//
+// For the full copyright and license information, please view
+// the license file that was distributed with this source code.
+//
+// AUTHOR:   Hirotaka Wakabayashi
+// CREATE:   Sun, 18 Oct 2026
+// REVISION:
+//
+
+package k2hash
+
+import (
+	// #cgo CFLAGS: -g -O2 -Wall -Wextra -Wno-unused-variable -Wno-unused-parameter -I. -I/usr/include/k2hash
+	// #cgo LDFLAGS: -L/usr/lib -lk2hash
+	// #include <errno.h>
+	// #include <stdlib.h>
+	// #include <string.h>
+	// #include "k2hash.h"
+	//
+	// // k2h_go_errno returns errno of a failed call, which is never zero.
+	// static int k2h_go_errno(void) {
+	//     return 0 != errno ? errno : -1;
+	// }
+	//
+	// // k2h_go_set_many sets count values. Keys and values are packed in buf.
+	// // errnos[i] is zero if the i-th value is set successfully.
+	// static void k2h_go_set_many(k2h_h handle, const unsigned char* buf, const size_t* keyoffs, const size_t* keylens,
+	//                             const size_t* valoffs, const size_t* vallens, int count, const char* pass,
+	//                             const time_t* expire, int* errnos) {
+	//     int i;
+	//     for (i = 0; i < count; ++i) {
+	//         errno = 0;
+	//         const unsigned char* pval = 0 < vallens[i] ? buf + valoffs[i] : NULL;
+	//         errnos[i] = k2h_set_value_wa(handle, buf + keyoffs[i], keylens[i], pval, vallens[i], pass, expire) ? 0 : k2h_go_errno();
+	//     }
+	// }
+	//
+	// // k2h_go_get_many gets count values. Keys are packed in buf, and values are packed in the returned
+	// // buffer which the caller must free. errnos[i] is zero if the i-th value is got successfully.
+	// static unsigned char* k2h_go_get_many(k2h_h handle, const unsigned char* buf, const size_t* keyoffs,
+	//                                       const size_t* keylens, int count, const char* pass, size_t* vallens,
+	//                                       int* errnos) {
+	//     int i;
+	//     size_t total = 0;
+	//     unsigned char** vals = calloc(count, sizeof(unsigned char*));
+	//     if (NULL == vals) {
+	//         for (i = 0; i < count; ++i) {
+	//             vallens[i] = 0;
+	//             errnos[i] = ENOMEM;
+	//         }
+	//         return NULL;
+	//     }
+	//     for (i = 0; i < count; ++i) {
+	//         errno = 0;
+	//         if (k2h_get_value_wp(handle, buf + keyoffs[i], keylens[i], &vals[i], &vallens[i], pass)) {
+	//             errnos[i] = 0;
+	//             total += vallens[i];
+	//         } else {
+	//             vals[i] = NULL;
+	//             vallens[i] = 0;
+	//             errnos[i] = k2h_go_errno();
+	//         }
+	//     }
+	//     unsigned char* result = malloc(0 < total ? total : 1);
+	//     size_t offset = 0;
+	//     for (i = 0; i < count; ++i) {
+	//         if (NULL == vals[i]) {
+	//             continue;
+	//         }
+	//         if (NULL == result) {
+	//             vallens[i] = 0;
+	//             errnos[i] = ENOMEM;
+	//         } else {
+	//             memcpy(result + offset, vals[i], vallens[i]);
+	//             offset += vallens[i];
+	//         }
+	//         free(vals[i]);
+	//     }
+	//     free(vals);
+	//     return result;
+	// }
+	//
+	// // k2h_go_remove_many removes count keys. Keys are packed in buf.
+	// // errnos[i] is zero if the i-th key is removed successfully.
+	// static void k2h_go_remove_many(k2h_h handle, const unsigned char* buf, const size_t* keyoffs,
+	//                                const size_t* keylens, int count, bool all, int* errnos) {
+	//     int i;
+	//     for (i = 0; i < count; ++i) {
+	//         errno = 0;
+	//         bool ok = all ? k2h_remove_all(handle, buf + keyoffs[i], keylens[i]) : k2h_remove(handle, buf + keyoffs[i], keylens[i]);
+	//         errnos[i] = ok ? 0 : k2h_go_errno();
+	//     }
+	// }
+	"C"
+)
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+// BatchResult holds the result of an item of a batch operation.
+type BatchResult struct {
+	// Key is the key of the item.
+	Key []byte
+	// Value is the value of the item got by GetMany. It is nil if Err is not nil.
+	Value *GetResult
+	// Err is the error of the item, if any.
+	Err error
+}
+
+// String returns a text representation of the object.
+func (r *BatchResult) String() string {
+	return fmt.Sprintf("[%v, %v, %v]", r.Key, r.Value, r.Err)
+}
+
+// batch is a set of byte sequences packed in a contiguous buffer, so that a whole batch is passed to C at once.
+// The buffer contains no Go pointers, so that it is passed to C without copying it to the C heap.
+type batch struct {
+	buf  []byte
+	offs []C.size_t
+	lens []C.size_t
+}
+
+// newBatch packs data in a contiguous buffer.
+func newBatch(data [][]byte) *batch {
+	size := 0
+	for _, d := range data {
+		size += len(d)
+	}
+	b := batch{
+		// buf must not be empty to take the address.
+		buf:  make([]byte, 0, size+1),
+		offs: make([]C.size_t, len(data)),
+		lens: make([]C.size_t, len(data)),
+	}
+	for i, d := range data {
+		b.offs[i] = C.size_t(len(b.buf))
+		b.lens[i] = C.size_t(len(d))
+		b.buf = append(b.buf, d...)
+	}
+	return &b
+}
+
+// ptr returns the pointer of the buffer.
+func (b *batch) ptr() *C.uchar {
+	return (*C.uchar)(unsafe.Pointer(&b.buf[:1][0]))
+}
+
+// batchErrno converts an errno returned by a batch C function to an error.
+func batchErrno(e C.int) error {
+	if e <= 0 {
+		return nil
+	}
+	return syscall.Errno(e)
+}
+
+// SetMany sets values of keys at once. keys and vals are either a []string or a [][]byte, and they must have the
+// same length. The result of each item is returned in the same order as keys.
+func (k2h *K2hash) SetMany(k interface{}, v interface{}, options ...func(*Params)) ([]BatchResult, error) {
+	// 1. binary or text
+	keys, err := toBytesSlice(k)
+	if err != nil {
+		return nil, err
+	}
+	vals, err := toBytesSlice(v)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) != len(vals) {
+		return nil, fmt.Errorf("%w: %v keys and %v values", ErrInvalid, len(keys), len(vals))
+	}
+	if err := k2h.checkWritable("SetMany", nil); err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return []BatchResult{}, nil
+	}
+
+	// 2. set params
+	params := Params{
+		password:           "",
+		expirationDuration: 0,
+	}
+	for _, option := range options {
+		option(&params)
+	}
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+
+	// 3. set in a batch
+	count := len(keys)
+	b := newBatch(append(append(make([][]byte, 0, count*2), keys...), vals...))
+	cPass := C.CString(params.password)
+	defer C.free(unsafe.Pointer(cPass))
+	var expire *C.time_t
+	// WARNING: You can't set zero expire.
+	if params.expirationDuration != 0 {
+		expire = (*C.time_t)(&params.expirationDuration)
+	}
+	errnos := make([]C.int, count)
+	C.k2h_go_set_many(k2h.handle, b.ptr(), &b.offs[0], &b.lens[0], &b.offs[count], &b.lens[count], C.int(count), cPass, expire, &errnos[0])
+
+	// 4. results
+	results := make([]BatchResult, count)
+	for i, key := range keys {
+		results[i].Key = key
+		if errnos[i] != 0 {
+			results[i].Err = newOpError("SetMany", key, batchErrno(errnos[i]), nil)
+		}
+	}
+	return results, nil
+}
+
+// GetMany gets values of keys at once. keys is either a []string or a [][]byte.
+// The result of each item is returned in the same order as keys.
+func (k2h *K2hash) GetMany(k interface{}, options ...func(*Params)) ([]BatchResult, error) {
+	// 1. binary or text
+	keys, err := toBytesSlice(k)
+	if err != nil {
+		return nil, err
+	}
+	if err := k2h.checkOpen("GetMany", nil); err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return []BatchResult{}, nil
+	}
+
+	// 2. set params
+	params := Params{
+		password:           "",
+		expirationDuration: 0,
+	}
+	for _, option := range options {
+		option(&params)
+	}
+
+	// 3. get in a batch
+	count := len(keys)
+	b := newBatch(keys)
+	cPass := C.CString(params.password)
+	defer C.free(unsafe.Pointer(cPass))
+	vallens := make([]C.size_t, count)
+	errnos := make([]C.int, count)
+	cVals := C.k2h_go_get_many(k2h.handle, b.ptr(), &b.offs[0], &b.lens[0], C.int(count), cPass, &vallens[0], &errnos[0])
+	defer C.free(unsafe.Pointer(cVals))
+	var total C.size_t
+	for _, l := range vallens {
+		total += l
+	}
+	vals := goBytes(cVals, total)
+
+	// 4. results
+	results := make([]BatchResult, count)
+	offset := 0
+	retried := false
+	for i, key := range keys {
+		results[i].Key = key
+		if errnos[i] != 0 {
+			// A value encrypted with a new passphrase is read again after the passphrases are reloaded.
+			if retried || k2h.retryDecrypt(key, &params) {
+				retried = true
+				val, err := k2h.getInto("GetMany", key, []byte{}, options...)
+				if err == nil {
+					results[i].Value = &GetResult{val: val}
+				}
+				results[i].Err = err
+				continue
+			}
+			results[i].Err = k2h.valueError("GetMany", key, batchErrno(errnos[i]))
+			continue
+		}
+		l := int(vallens[i])
+		results[i].Value = &GetResult{
+			val: vals[offset : offset+l : offset+l],
+		}
+		offset += l
+	}
+	return results, nil
+}
+
+// RemoveMany removes keys at once. keys is either a []string or a [][]byte. WithRemoveAll is available,
+// but WithRemoveSubKey is not. The result of each item is returned in the same order as keys.
+func (k2h *K2hash) RemoveMany(k interface{}, options ...func(*RemoveParams)) ([]BatchResult, error) {
+	// 1. binary or text
+	keys, err := toBytesSlice(k)
+	if err != nil {
+		return nil, err
+	}
+	if err := k2h.checkWritable("RemoveMany", nil); err != nil {
+		return nil, err
+	}
+
+	// 2. remove params
+	params := RemoveParams{
+		all:    false,
+		subkey: nil,
+	}
+	for _, option := range options {
+		option(&params)
+	}
+	if params.subkey != nil {
+		return nil, fmt.Errorf("%w: RemoveMany can't remove a subkey", ErrInvalid)
+	}
+	if len(keys) == 0 {
+		return []BatchResult{}, nil
+	}
+
+	// 3. remove in a batch
+	count := len(keys)
+	b := newBatch(keys)
+	errnos := make([]C.int, count)
+	C.k2h_go_remove_many(k2h.handle, b.ptr(), &b.offs[0], &b.lens[0], C.int(count), C._Bool(params.all), &errnos[0])
+
+	// 4. results
+	results := make([]BatchResult, count)
+	for i, key := range keys {
+		results[i].Key = key
+		if errnos[i] != 0 {
+			results[i].Err = k2h.keyError("RemoveMany", key, batchErrno(errnos[i]))
+		}
+	}
+	return results, nil
+}
+
+// Local Variables:
+// c-basic-offset: 4
+// tab-width: 4
+// indent-tabs-mode: t
+// End:
+// vim600: noexpandtab sw=4 ts=4 fdm=marker
+// vim<600: noexpandtab sw=4 ts=4
@@ -0,0 +1,129 @@
+//
+// k2hash_go
+//
+// Copyright 2018 Yahoo Japan Corporation.
+//
+// Go driver for k2hash that is a NoSQL Key Value Store(KVS) library.
+// For k2hash, see https://github.com/yahoojapan/k2hash for the details.
+//
+// For the full copyright and license information, please view
+// the license file that was distributed with this source code.
+//
+// AUTHOR:   Hirotaka Wakabayashi
+// CREATE:   Sun, 18 Oct 2026
+// REVISION:
+//
+
+package k2hash
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrCodec is matched by errors.Is for errors of codecs, which are returned as a CodecError.
+var ErrCodec = errors.New("k2hash: codec failed")
+
+// Codec encodes and decodes values saved by a Store.
+type Codec interface {
+	// Marshal returns the encoding of v.
+	Marshal(v any) ([]byte, error)
+	// Unmarshal decodes data and stores the result in the value pointed to by v.
+	// It must not keep data after it returns, because data may be reused by a BufferPool.
+	Unmarshal(data []byte, v any) error
+}
+
+// CodecError is the error type returned if a Codec fails, so that it is distinguished from an OpError.
+type CodecError struct {
+	// Op is the name of the operation, like "Store.Get".
+	Op string
+	// Key is the key of the operation.
+	Key []byte
+	// Err is the error returned by the codec.
+	Err error
+}
+
+// Error returns a text representation of the error.
+func (e *CodecError) Error() string {
+	return fmt.Sprintf("k2hash: %v %q: codec: %v", e.Op, trimNull(e.Key), e.Err)
+}
+
+// Unwrap returns the error returned by the codec.
+func (e *CodecError) Unwrap() error {
+	return e.Err
+}
+
+// Is returns true if target is ErrCodec.
+func (e *CodecError) Is(target error) bool {
+	return target == ErrCodec
+}
+
+// JSONCodec encodes values by encoding/json.
+type JSONCodec struct{}
+
+// Marshal returns the JSON encoding of v.
+func (JSONCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal decodes JSON data to v.
+func (JSONCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// GobCodec encodes values by encoding/gob.
+type GobCodec struct{}
+
+// Marshal returns the gob encoding of v.
+func (GobCodec) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Unmarshal decodes gob data to v.
+func (GobCodec) Unmarshal(data []byte, v any) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+// RawCodec saves []byte and string values as they are.
+type RawCodec struct{}
+
+// Marshal returns v if it is []byte or string.
+func (RawCodec) Marshal(v any) ([]byte, error) {
+	switch val := v.(type) {
+	case []byte:
+		return val, nil
+	case string:
+		return []byte(val), nil
+	default:
+		return nil, fmt.Errorf("unsupported data format %T", v)
+	}
+}
+
+// Unmarshal copies data to v if it is *[]byte or *string.
+func (RawCodec) Unmarshal(data []byte, v any) error {
+	switch val := v.(type) {
+	case *[]byte:
+		*val = append([]byte{}, data...)
+		return nil
+	case *string:
+		*val = string(data)
+		return nil
+	default:
+		return fmt.Errorf("unsupported data format %T", v)
+	}
+}
+
+// Local Variables:
+// c-basic-offset: 4
+// tab-width: 4
+// indent-tabs-mode: t
+// End:
+// vim600: noexpandtab sw=4 ts=4 fdm=marker
+// vim<600: noexpandtab sw=4 ts=4
@@ -0,0 +1,232 @@
+//
+// k2hash_go
+//
+// Copyright 2018 Yahoo Japan Corporation.
+//
+// Go driver for k2hash that is a NoSQL Key Value Store(KVS) library.
+// For k2hash, see https://github.com/yahoojapan/k2hash for the details.
+//
+// For the full copyright and license information, please view
+// the license file that was distributed with this source code.
+//
+// AUTHOR:   Hirotaka Wakabayashi
+// CREATE:   Sun, 18 Oct 2026
+// REVISION:
+//
+
+package k2hash
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// ContextStats holds statistics of operations abandoned by the context variants.
+type ContextStats struct {
+	// Abandoned is the number of operations which kept running after ctx was done.
+	Abandoned uint64
+	// Undone is the number of abandoned operations which are undone, like a popped value pushed back.
+	Undone uint64
+	// UndoFailed is the number of abandoned operations which failed to be undone.
+	UndoFailed uint64
+	// LastUndoErr is the error of the last failed undo, if any.
+	LastUndoErr error
+}
+
+// String returns a text representation of the object.
+func (s ContextStats) String() string {
+	return fmt.Sprintf("[%v, %v, %v, %v]", s.Abandoned, s.Undone, s.UndoFailed, s.LastUndoErr)
+}
+
+// inflightOps counts operations abandoned by the context variants which are still running.
+type inflightOps struct {
+	sync.WaitGroup
+	// mu protects stats.
+	mu sync.Mutex
+	// stats is the statistics.
+	stats ContextStats
+}
+
+// abandoned records the result of an abandoned operation. undone is false if it needs no undo.
+func (o *inflightOps) abandoned(undone bool, err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.stats.Abandoned++
+	if err != nil {
+		o.stats.UndoFailed++
+		o.stats.LastUndoErr = err
+	} else if undone {
+		o.stats.Undone++
+	}
+}
+
+// ContextStats returns statistics of operations abandoned by the context variants of the handle and
+// its queues. A value popped by an abandoned operation is lost if UndoFailed is counted.
+func (k2h *K2hash) ContextStats() ContextStats {
+	k2h.inflight.mu.Lock()
+	defer k2h.inflight.mu.Unlock()
+	return k2h.inflight.stats
+}
+
+// runContext runs fn in a new goroutine and waits for it to return or ctx to be done.
+// A C function can't be interrupted, so that fn keeps running until it returns even if ctx is done.
+// In that case runContext returns ctx.Err() immediately, fn frees its own C memory as usual, and undo is
+// called with the result of fn if it succeeds and undo isn't nil. The abandoned fn is counted by inflight
+// so that the handle is not closed while it is running, and the result of undo is recorded in the stats.
+func runContext[T any](ctx context.Context, inflight *inflightOps, fn func() (T, error), undo func(T) error) (T, error) {
+	var zero T
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+	type result struct {
+		val T
+		err error
+	}
+	var mu sync.Mutex
+	abandoned := false
+	done := make(chan result, 1)
+	inflight.Add(1)
+	go func() {
+		defer inflight.Done()
+		val, err := fn()
+		mu.Lock()
+		defer mu.Unlock()
+		if abandoned {
+			if err == nil && undo != nil {
+				inflight.abandoned(true, undo(val))
+			} else {
+				inflight.abandoned(false, nil)
+			}
+			return
+		}
+		done <- result{val, err}
+	}()
+	select {
+	case r := <-done:
+		return r.val, r.err
+	case <-ctx.Done():
+		mu.Lock()
+		defer mu.Unlock()
+		select {
+		case r := <-done:
+			// fn returned at the same time. Don't discard the result.
+			return r.val, r.err
+		default:
+			abandoned = true
+			return zero, ctx.Err()
+		}
+	}
+}
+
+/* -- K2hash methods -- */
+
+// GetContext is the same as Get, but it returns ctx.Err() if ctx is done before Get returns.
+func (k2h *K2hash) GetContext(ctx context.Context, k interface{}, options ...func(*Params)) (*GetResult, error) {
+	return runContext(ctx, &k2h.inflight, func() (*GetResult, error) {
+		return k2h.Get(k, options...)
+	}, nil)
+}
+
+// SetContext is the same as Set, but it returns ctx.Err() if ctx is done before Set returns.
+// The value may be saved after SetContext returns in that case.
+func (k2h *K2hash) SetContext(ctx context.Context, k interface{}, v interface{}, options ...func(*Params)) (bool, error) {
+	return runContext(ctx, &k2h.inflight, func() (bool, error) {
+		return k2h.Set(k, v, options...)
+	}, nil)
+}
+
+// RemoveContext is the same as Remove, but it returns ctx.Err() if ctx is done before Remove returns.
+// The key may be removed after RemoveContext returns in that case.
+func (k2h *K2hash) RemoveContext(ctx context.Context, k interface{}, options ...func(*RemoveParams)) (bool, error) {
+	return runContext(ctx, &k2h.inflight, func() (bool, error) {
+		return k2h.Remove(k, options...)
+	}, nil)
+}
+
+// LoadFromFileContext is the same as LoadFromFile, but it returns ctx.Err() if ctx is done before LoadFromFile returns.
+// The data may be loaded after LoadFromFileContext returns in that case.
+func (k2h *K2hash) LoadFromFileContext(ctx context.Context, file string, ignoreError bool) (bool, error) {
+	return runContext(ctx, &k2h.inflight, func() (bool, error) {
+		return k2h.LoadFromFile(file, ignoreError)
+	}, nil)
+}
+
+// DumpToFileContext is the same as DumpToFile, but it returns ctx.Err() if ctx is done before DumpToFile returns.
+// The file may be written after DumpToFileContext returns in that case.
+func (k2h *K2hash) DumpToFileContext(ctx context.Context, file string, ignoreError bool) (bool, error) {
+	return runContext(ctx, &k2h.inflight, func() (bool, error) {
+		return k2h.DumpToFile(file, ignoreError)
+	}, nil)
+}
+
+/* -- Queue methods -- */
+
+// PushContext is the same as Push, but it returns ctx.Err() if ctx is done before Push returns.
+// The value may be pushed after PushContext returns in that case.
+func (q *Queue) PushContext(ctx context.Context, v interface{}, options ...func(*Params)) (bool, error) {
+	return runContext(ctx, q.inflight, func() (bool, error) {
+		return q.Push(v, options...)
+	}, nil)
+}
+
+// PopContext is the same as Pop, but it returns ctx.Err() if ctx is done before Pop returns.
+func (q *Queue) PopContext(ctx context.Context, options ...func(*Params)) (string, error) {
+	val, err := q.PopBytesContext(ctx, options...)
+	if err != nil {
+		return "", err
+	}
+	return string(trimNull(val)), nil
+}
+
+// PopBytesContext is the same as PopBytes, but it returns ctx.Err() if ctx is done before PopBytes returns.
+// A value popped after PopBytesContext returns is pushed back to the queue with the same password, so that
+// it is not lost, but its position in a FIFO queue and its expiration are not kept.
+func (q *Queue) PopBytesContext(ctx context.Context, options ...func(*Params)) ([]byte, error) {
+	return runContext(ctx, q.inflight, func() ([]byte, error) {
+		return q.PopBytes(options...)
+	}, func(val []byte) error {
+		_, err := q.Push(val, options...)
+		return err
+	})
+}
+
+/* -- KeyQueue methods -- */
+
+// PushContext is the same as Push, but it returns ctx.Err() if ctx is done before Push returns.
+// The key may be pushed after PushContext returns in that case.
+func (q *KeyQueue) PushContext(ctx context.Context, v interface{}, options ...func(*Params)) (bool, error) {
+	return runContext(ctx, q.inflight, func() (bool, error) {
+		return q.Push(v, options...)
+	}, nil)
+}
+
+// PopContext is the same as Pop, but it returns ctx.Err() if ctx is done before Pop returns.
+func (q *KeyQueue) PopContext(ctx context.Context, options ...func(*Params)) (string, error) {
+	val, err := q.PopBytesContext(ctx, options...)
+	if err != nil {
+		return "", err
+	}
+	return string(trimNull(val)), nil
+}
+
+// PopBytesContext is the same as PopBytes, but it returns ctx.Err() if ctx is done before PopBytes returns.
+// A key popped after PopBytesContext returns is pushed back to the queue with the same password, so that
+// it is not lost, but its position in a FIFO queue and its expiration are not kept.
+func (q *KeyQueue) PopBytesContext(ctx context.Context, options ...func(*Params)) ([]byte, error) {
+	kv, err := runContext(ctx, q.inflight, func() ([2][]byte, error) {
+		return q.popKeyVal("KeyQueue.Pop", options...)
+	}, func(kv [2][]byte) error {
+		_, err := q.Push(kv[0], options...)
+		return err
+	})
+	return kv[1], err
+}
+
+// Local Variables:
+// c-basic-offset: 4
+// tab-width: 4
+// indent-tabs-mode: t
+// End:
+// vim600: noexpandtab sw=4 ts=4 fdm=marker
+// vim<600: noexpandtab sw=4 ts=4
@@ -0,0 +1,333 @@
+//
+// k2hash_go
+//
+// Copyright 2018 Yahoo Japan Corporation.
+//
+// Go driver for k2hash that is a NoSQL Key Value Store(KVS) library.
+// For k2hash, see https://github.com/yahoojapan/k2hash for the details.
+//
+// For the full copyright and license information, please view
+// the license file that was distributed with this source code.
+//
+// AUTHOR:   Hirotaka Wakabayashi
+// CREATE:   Sun, 18 Oct 2026
+// REVISION:
+//
+
+package k2hash
+
+import (
+	// #cgo CFLAGS: -g -O2 -Wall -Wextra -Wno-unused-variable -Wno-unused-parameter -I. -I/usr/include/k2hash
+	// #cgo LDFLAGS: -L/usr/lib -lk2hash
+	// #include <errno.h>
+	// #include <stdlib.h>
+	// #include <string.h>
+	// #include "k2hash.h"
+	//
+	// // k2h_go_attrpack_equal returns true if two attribute packs are the same.
+	// static bool k2h_go_attrpack_equal(const PK2HATTRPCK pa, int acnt, const PK2HATTRPCK pb, int bcnt) {
+	//     int i;
+	//     if (acnt != bcnt) {
+	//         return false;
+	//     }
+	//     for (i = 0; i < acnt; ++i) {
+	//         if (pa[i].keylength != pb[i].keylength || pa[i].vallength != pb[i].vallength ||
+	//             0 != memcmp(pa[i].pkey, pb[i].pkey, pa[i].keylength) || 0 != memcmp(pa[i].pval, pb[i].pval, pa[i].vallength)) {
+	//             return false;
+	//         }
+	//     }
+	//     return true;
+	// }
+	//
+	// // k2h_go_get_entry gets the value, the subkeys and the attributes of a key. It reads the attributes again
+	// // after all, and retries up to retry times if they are changed, so that all parts are read consistently
+	// // while the mtime attribute is enabled. The caller must free all parts even if it returns false.
+	// static bool k2h_go_get_entry(k2h_h handle, const unsigned char* pkey, size_t keylength, const char* pass, int retry,
+	//                              unsigned char** ppval, size_t* pvallength, PK2HKEYPCK* ppskeypck, int* pskeypckcnt,
+	//                              PK2HATTRPCK* ppattrspck, int* pattrspckcnt) {
+	//     for (;;) {
+	//         PK2HATTRPCK pafter = NULL;
+	//         int aftercnt = 0;
+	//         *ppval = NULL;
+	//         *pvallength = 0;
+	//         *ppskeypck = NULL;
+	//         *pskeypckcnt = 0;
+	//         *ppattrspck = NULL;
+	//         *pattrspckcnt = 0;
+	//         // A key without attributes or subkeys is not an error.
+	//         if (!k2h_get_attrs(handle, pkey, keylength, ppattrspck, pattrspckcnt)) {
+	//             *ppattrspck = NULL;
+	//             *pattrspckcnt = 0;
+	//         }
+	//         if (!k2h_get_value_wp(handle, pkey, keylength, ppval, pvallength, pass)) {
+	//             return false;
+	//         }
+	//         if (!k2h_get_subkeys(handle, pkey, keylength, ppskeypck, pskeypckcnt)) {
+	//             *ppskeypck = NULL;
+	//             *pskeypckcnt = 0;
+	//         }
+	//         if (!k2h_get_attrs(handle, pkey, keylength, &pafter, &aftercnt)) {
+	//             pafter = NULL;
+	//             aftercnt = 0;
+	//         }
+	//         bool same = k2h_go_attrpack_equal(*ppattrspck, *pattrspckcnt, pafter, aftercnt);
+	//         k2h_free_attrpack(pafter, aftercnt);
+	//         if (same || retry-- <= 0) {
+	//             return true;
+	//         }
+	//         free(*ppval);
+	//         k2h_free_keypack(*ppskeypck, *pskeypckcnt);
+	//         k2h_free_attrpack(*ppattrspck, *pattrspckcnt);
+	//     }
+	// }
+	//
+	// // k2h_go_set_entry writes the value, the subkeys and the attributes to the staging key, and renames it to
+	// // the key, so that the key is replaced with all parts at once or left as it is. A staging key left by
+	// // a crash is removed first, and the staging key is removed if an error occurs.
+	// static bool k2h_go_set_entry(k2h_h handle, const unsigned char* pkey, size_t keylength, const unsigned char* pstaging,
+	//                              size_t staginglength, const unsigned char* pval, size_t vallength, const PK2HKEYPCK pskeypck,
+	//                              int skeypckcnt, const PK2HATTRPCK pattrspck, int attrspckcnt, const char* pass,
+	//                              const time_t* expire) {
+	//     int i;
+	//     int saved;
+	//     k2h_remove(handle, pstaging, staginglength);
+	//     if (!k2h_set_all_wa(handle, pstaging, staginglength, pval, vallength, pskeypck, skeypckcnt, pass, expire)) {
+	//         goto failed;
+	//     }
+	//     for (i = 0; i < attrspckcnt; ++i) {
+	//         if (!k2h_add_attr(handle, pstaging, staginglength, pattrspck[i].pkey, pattrspck[i].keylength, pattrspck[i].pval, pattrspck[i].vallength)) {
+	//             goto failed;
+	//         }
+	//     }
+	//     if (k2h_rename(handle, pstaging, staginglength, pkey, keylength)) {
+	//         return true;
+	//     }
+	// failed:
+	//     saved = errno;
+	//     k2h_remove(handle, pstaging, staginglength);
+	//     errno = saved;
+	//     return false;
+	// }
+	"C"
+)
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// entryRetry is the max number of retries of GetEntry while the entry is updated by others.
+const entryRetry = 3
+
+// Entry holds a value with the subkeys and the attributes of it.
+type Entry struct {
+	// Value is the value in binary format. A value saved as a string contains the null termination.
+	Value []byte
+	// SubKeys is the subkeys in binary format. A subkey saved as a string contains the null termination.
+	SubKeys [][]byte
+	// Attrs is the attributes.
+	Attrs Attrs
+	// Mtime is the modification time set by the builtin attribute plugin. It is zero if mtime is disabled.
+	Mtime time.Time
+	// Expire is the expiration time set by the builtin attribute plugin. It is zero if the value never expires.
+	Expire time.Time
+}
+
+// String returns a text representation of the object.
+func (e *Entry) String() string {
+	return fmt.Sprintf("[%v, %v, %v, %v, %v]", e.Value, e.SubKeys, e.Attrs, e.Mtime, e.Expire)
+}
+
+// GetEntry returns the value, the subkeys and the attributes of a key in one lookup.
+// The attributes are read again after all, and the lookup is retried if they are changed by others,
+// so that all parts are consistent if the mtime attribute is enabled.
+func (k2h *K2hash) GetEntry(k interface{}, options ...func(*Params)) (*Entry, error) {
+	// 1. binary or text
+	key, err := toBytes(k)
+	if err != nil {
+		return nil, err
+	}
+	if err := k2h.checkOpen("GetEntry", key); err != nil {
+		return nil, err
+	}
+
+	// 2. set params
+	params := Params{
+		password:           "",
+		expirationDuration: 0,
+	}
+	for _, option := range options {
+		option(&params)
+	}
+
+	// 3. get all parts
+	cKey, cKeyLen := cBytes(key)
+	defer C.free(unsafe.Pointer(cKey))
+	cPass := C.CString(params.password)
+	defer C.free(unsafe.Pointer(cPass))
+	var cVal *C.uchar
+	var cValLen C.size_t
+	var keypack C.PK2HKEYPCK
+	var keypackCnt C.int
+	var attrpack C.PK2HATTRPCK
+	var attrpackCnt C.int
+	ok, errno := C.k2h_go_get_entry(k2h.handle, cKey, cKeyLen, cPass, C.int(entryRetry),
+		&cVal, &cValLen, &keypack, &keypackCnt, &attrpack, &attrpackCnt)
+	if ok != true && k2h.retryDecrypt(key, &params) {
+		C.free(unsafe.Pointer(cVal))
+		C.k2h_free_keypack(keypack, keypackCnt)
+		C.k2h_free_attrpack(attrpack, attrpackCnt)
+		ok, errno = C.k2h_go_get_entry(k2h.handle, cKey, cKeyLen, cPass, C.int(entryRetry),
+			&cVal, &cValLen, &keypack, &keypackCnt, &attrpack, &attrpackCnt)
+	}
+	defer C.free(unsafe.Pointer(cVal))
+	defer C.k2h_free_keypack(keypack, keypackCnt)
+	defer C.k2h_free_attrpack(attrpack, attrpackCnt)
+	if ok != true {
+		return nil, k2h.valueError("GetEntry", key, errno)
+	}
+
+	// 4. copy all parts
+	attrs := goAttrs(attrpack, attrpackCnt)
+	e := Entry{
+		Value:   goBytes(cVal, cValLen),
+		SubKeys: goKeys(keypack, keypackCnt),
+		Attrs:   attrs,
+		Mtime:   attrs.Mtime(),
+		Expire:  attrs.Expire(),
+	}
+	return &e, nil
+}
+
+// SetEntry sets the value, the subkeys and the attributes of a key at once. All parts are written to a staging
+// key first, and the staging key is renamed to the key, so that others read either the old entry or the new one.
+// If an error is returned, the key is left as it is.
+// An attribute of the entry replaces the existing one with the same name, and the other existing attributes
+// are kept unless WithReplaceAttrs is given. Entry.Expire is used as the expiration time unless WithExpire
+// is given, and Entry.Mtime is ignored. The builtin attributes like mtime and expire in Entry.Attrs are ignored,
+// because they are maintained by the builtin attribute plugin.
+func (k2h *K2hash) SetEntry(k interface{}, e Entry, options ...func(*Params)) (bool, error) {
+	// 1. binary or text
+	key, err := toBytes(k)
+	if err != nil {
+		return false, err
+	}
+	if err := k2h.checkWritable("SetEntry", key); err != nil {
+		return false, err
+	}
+
+	// 2. set params
+	params := Params{
+		password:           "",
+		expirationDuration: 0,
+		replaceAttrs:       false,
+	}
+	for _, option := range options {
+		option(&params)
+	}
+	if params.expirationDuration == 0 && !e.Expire.IsZero() {
+		d := time.Until(e.Expire)
+		if d <= 0 {
+			return false, fmt.Errorf("%w: expiration time %v is in the past", ErrInvalid, e.Expire)
+		}
+		params.expirationDuration = int64((d + time.Second - 1) / time.Second)
+	}
+	if err := params.validate(); err != nil {
+		return false, err
+	}
+
+	// 3. attributes except the builtin ones
+	defer k2h.entries.lock(key)()
+	var names, vals [][]byte
+	given := make(map[string]bool)
+	for _, a := range e.Attrs {
+		if isBuiltinAttr(a.name) {
+			continue
+		}
+		name, _ := toBytes(a.name)
+		names = append(names, name)
+		vals = append(vals, a.val)
+		given[a.name] = true
+	}
+	if !params.replaceAttrs {
+		oldNames, oldVals, err := k2h.attrPack("SetEntry", key)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return false, err
+		}
+		for i := range oldNames {
+			name := string(trimNull(oldNames[i]))
+			if !given[name] && !isBuiltinAttr(name) {
+				names = append(names, oldNames[i])
+				vals = append(vals, oldVals[i])
+			}
+		}
+	}
+
+	// 4. write all parts
+	return k2h.writeEntry("SetEntry", key, e.Value, e.SubKeys, names, vals, params)
+}
+
+// stagingKeyPrefix is the prefix of the staging key, which is hidden from Cursor and the scan APIs.
+const stagingKeyPrefix = "\x00k2hash_go:staging:"
+
+// isStagingKey returns true if the key is a staging key.
+func isStagingKey(key []byte) bool {
+	return bytes.HasPrefix(key, []byte(stagingKeyPrefix))
+}
+
+// writeEntry replaces a key with the value, the subkeys and the attributes through the staging key.
+// The attribute names are written as they are. The caller must lock the key.
+func (k2h *K2hash) writeEntry(op string, key []byte, val []byte, skeys [][]byte, names, vals [][]byte, params Params) (bool, error) {
+	attrpack := newAttrPack(names, vals)
+	defer freeAttrPack(attrpack, len(names))
+	cKey, cKeyLen := cBytes(key)
+	defer C.free(unsafe.Pointer(cKey))
+	cStaging, cStagingLen := cBytes(append([]byte(stagingKeyPrefix), key...))
+	defer C.free(unsafe.Pointer(cStaging))
+	cVal, cValLen := cBytes(val)
+	defer C.free(unsafe.Pointer(cVal))
+	keypack := newKeyPack(skeys)
+	defer freeKeyPack(keypack, len(skeys))
+	cPass := C.CString(params.password)
+	defer C.free(unsafe.Pointer(cPass))
+	var expire *C.time_t
+	// WARNING: You can't set zero expire.
+	if params.expirationDuration != 0 {
+		expire = (*C.time_t)(&params.expirationDuration)
+	}
+	ok, errno := C.k2h_go_set_entry(k2h.handle, cKey, cKeyLen, cStaging, cStagingLen, cVal, cValLen, keypack,
+		C.int(len(skeys)), attrpack, C.int(len(names)), cPass, expire)
+	if ok != true {
+		return false, newOpError(op, key, errno, nil)
+	}
+	return true, nil
+}
+
+// entryLockCount is the number of locks of entryLocks.
+const entryLockCount = 64
+
+// entryLocks is a set of locks which serializes the rewrites of the same key in a process,
+// because they share the staging key.
+type entryLocks [entryLockCount]sync.Mutex
+
+// lock locks the key and returns the function to unlock it.
+func (l *entryLocks) lock(key []byte) func() {
+	h := fnv.New32a()
+	h.Write(key)
+	m := &l[h.Sum32()%entryLockCount]
+	m.Lock()
+	return m.Unlock
+}
+
+// Local Variables:
+// c-basic-offset: 4
+// tab-width: 4
+// indent-tabs-mode: t
+// End:
+// vim600: noexpandtab sw=4 ts=4 fdm=marker
+// vim<600: noexpandtab sw=4 ts=4
@@ -0,0 +1,172 @@
+//
+// k2hash_go
+//
+// Copyright 2018 Yahoo Japan Corporation.
+//
+// Go driver for k2hash that is a NoSQL Key Value Store(KVS) library.
+// For k2hash, see https://github.com/yahoojapan/k2hash for the details.
+//
+// For the full copyright and license information, please view
+// the license file that was distributed with this source code.
+//
+// AUTHOR:   Hirotaka Wakabayashi
+// CREATE:   Sun, 18 Oct 2026
+// REVISION:
+//
+
+package k2hash
+
+import (
+	// #cgo CFLAGS: -g -O2 -Wall -Wextra -Wno-unused-variable -Wno-unused-parameter -I. -I/usr/include/k2hash
+	// #cgo LDFLAGS: -L/usr/lib -lk2hash
+	// #include <stdlib.h>
+	// #include "k2hash.h"
+	"C"
+)
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	// ErrNotFound is returned if a key or a value doesn't exist.
+	ErrNotFound = errors.New("k2hash: not found")
+	// ErrClosed is returned if a handle is already closed.
+	ErrClosed = errors.New("k2hash: handle closed")
+	// ErrReadOnly is returned if a k2hash file is opened in read only mode.
+	ErrReadOnly = errors.New("k2hash: read only")
+	// ErrDecrypt is returned if an encrypted value can't be decrypted by the passphrases.
+	ErrDecrypt = errors.New("k2hash: decryption failed")
+	// ErrQueueEmpty is returned if a queue has no elements.
+	ErrQueueEmpty = errors.New("k2hash: queue is empty")
+	// ErrInvalid is returned if an argument or an option is invalid.
+	ErrInvalid = errors.New("k2hash: invalid argument")
+	// ErrExist is returned if a key to be created already exists.
+	ErrExist = errors.New("k2hash: already exists")
+)
+
+// OpError is the error type returned by k2hash operations.
+type OpError struct {
+	// Op is the name of the operation, like "Get".
+	Op string
+	// Key is the key of the operation, if any.
+	Key []byte
+	// Errno is errno set by libk2hash, if any.
+	Errno syscall.Errno
+	// Err is the cause of the error, like ErrNotFound.
+	Err error
+}
+
+// errPrefix is the prefix of the error messages of this package.
+const errPrefix = "k2hash: "
+
+// Error returns a text representation of the error. The prefix of the cause is dropped, so that
+// the message looks like `k2hash: Get "key": not found`.
+func (e *OpError) Error() string {
+	s := errPrefix + e.Op
+	if e.Key != nil {
+		s += fmt.Sprintf(" %q", trimNull(e.Key))
+	}
+	switch {
+	case e.Err != nil:
+		s += ": " + strings.TrimPrefix(e.Err.Error(), errPrefix)
+	case e.Errno != 0:
+		s += ": " + e.Errno.Error()
+	default:
+		s += ": failed"
+	}
+	return s
+}
+
+// Unwrap returns the cause of the error.
+func (e *OpError) Unwrap() error {
+	if e.Err != nil {
+		return e.Err
+	}
+	if e.Errno != 0 {
+		return e.Errno
+	}
+	return nil
+}
+
+// Is reports whether the error matches target. It also matches errno set by libk2hash.
+func (e *OpError) Is(target error) bool {
+	if errno, ok := target.(syscall.Errno); ok {
+		return e.Errno != 0 && e.Errno == errno
+	}
+	return false
+}
+
+// newOpError returns an OpError of op. errno is an error returned by a cgo call.
+func newOpError(op string, key []byte, errno error, err error) *OpError {
+	e := &OpError{
+		Op:  op,
+		Key: key,
+		Err: err,
+	}
+	if n, ok := errno.(syscall.Errno); ok {
+		e.Errno = n
+	}
+	return e
+}
+
+// checkOpen returns ErrClosed if the k2hash file is already closed.
+func (k2h *K2hash) checkOpen(op string, key []byte) error {
+	if k2h.handle == C.K2H_INVALID_HANDLE {
+		return newOpError(op, key, nil, ErrClosed)
+	}
+	return nil
+}
+
+// checkWritable returns ErrClosed or ErrReadOnly if the k2hash file can't be modified.
+func (k2h *K2hash) checkWritable(op string, key []byte) error {
+	if err := k2h.checkOpen(op, key); err != nil {
+		return err
+	}
+	if k2h.readonly {
+		return newOpError(op, key, nil, ErrReadOnly)
+	}
+	return nil
+}
+
+// exists returns true if the key exists regardless of the attributes of it.
+func (k2h *K2hash) exists(key []byte) bool {
+	cKey, cKeyLen := cBytes(key)
+	defer C.free(unsafe.Pointer(cKey))
+	var cRetValue *C.uchar
+	var cRetValueLen C.size_t
+	ok := C.k2h_get_value_np(k2h.handle, cKey, cKeyLen, &cRetValue, &cRetValueLen)
+	defer C.free(unsafe.Pointer(cRetValue))
+	return ok == true
+}
+
+// keyError returns an OpError of op failed with errno. The cause is ErrNotFound if the key doesn't exist.
+func (k2h *K2hash) keyError(op string, key []byte, errno error) error {
+	if !k2h.exists(key) {
+		return newOpError(op, key, errno, ErrNotFound)
+	}
+	return newOpError(op, key, errno, nil)
+}
+
+// valueError returns an OpError of op failed to read the value of the key with errno.
+// The cause is ErrDecrypt if the key exists with an encrypted value, or ErrNotFound otherwise.
+func (k2h *K2hash) valueError(op string, key []byte, errno error) error {
+	if k2h.exists(key) {
+		if attrs, _ := k2h.GetAttrs(key); attrs.IsEncrypted() {
+			return newOpError(op, key, errno, ErrDecrypt)
+		}
+	}
+	return newOpError(op, key, errno, ErrNotFound)
+}
+
+// Local Variables:
+// c-basic-offset: 4
+// tab-width: 4
+// indent-tabs-mode: t
+// End:
+// vim600: noexpandtab sw=4 ts=4 fdm=marker
+// vim<600: noexpandtab sw=4 ts=4
@@ -25,34 +25,76 @@ import (
 )
 
 import (
-	"fmt"
 	"unsafe"
 )
 
 // GetResult holds the result of Get.
 type GetResult struct {
-	val []byte // text or binary
+	val  []byte      // text or binary
+	pool *BufferPool // pool of val, if any
+	buf  *[]byte     // buffer of val got from pool
 }
 
-// Bytes returns the value in binary format.
+// Bytes returns the value in binary format as it is saved. A value saved as a string contains
+// the null termination, which String removes.
 func (r *GetResult) Bytes() []byte {
 	return r.val
 }
 
-// String returns the value in text format.
+// String returns the value in text format without the null termination.
 func (r *GetResult) String() string {
-	return string(r.val)
+	return string(trimNull(r.val))
+}
+
+// Release returns the buffer of the value to the pool given by WithBufferPool.
+// The value must not be used after Release. It does nothing if no pool is used.
+func (r *GetResult) Release() {
+	if r.pool == nil {
+		return
+	}
+	r.pool.put(r.buf, r.val)
+	r.val = nil
+	r.pool = nil
+	r.buf = nil
 }
 
 // Get returns data from a k2hash file.
 func (k2h *K2hash) Get(k interface{}, options ...func(*Params)) (*GetResult, error) {
+	if k2h.pool == nil {
+		val, err := k2h.getInto("Get", k, nil, options...)
+		if err != nil {
+			return nil, err
+		}
+		if val == nil {
+			val = []byte{}
+		}
+		return &GetResult{val: val}, nil
+	}
+	buf := k2h.pool.get()
+	val, err := k2h.getInto("Get", k, *buf, options...)
+	if err != nil {
+		k2h.pool.put(buf, val)
+		return nil, err
+	}
+	return &GetResult{val: val, pool: k2h.pool, buf: buf}, nil
+}
+
+// GetInto appends the value to dst and returns the extended buffer. The value is copied only once from the
+// C heap, so that no allocation occurs if dst has enough capacity.
+// A value saved as a string contains the null termination.
+func (k2h *K2hash) GetInto(k interface{}, dst []byte, options ...func(*Params)) ([]byte, error) {
+	return k2h.getInto("GetInto", k, dst, options...)
+}
+
+// getInto appends the value to dst.
+func (k2h *K2hash) getInto(op string, k interface{}, dst []byte, options ...func(*Params)) ([]byte, error) {
 	// 1. binary or text
-	var key string
-	switch k.(type) {
-	default:
-		return nil, fmt.Errorf("unsupported key data format %T", key)
-	case string:
-		key = k.(string)
+	key, err := toBytes(k)
+	if err != nil {
+		return dst, err
+	}
+	if err := k2h.checkOpen(op, key); err != nil {
+		return dst, err
 	}
 
 	// 2. set params
@@ -66,22 +108,26 @@ func (k2h *K2hash) Get(k interface{}, options ...func(*Params)) (*GetResult, err
 	}
 
 	// 3. get from k2hash get API
-	cKey := C.CString(key)
+	cKey, cKeyLen := cBytes(key)
 	defer C.free(unsafe.Pointer(cKey))
-	var cRetValue *C.char
-	defer C.free(unsafe.Pointer(cRetValue))
 	cPass := C.CString(params.password)
 	defer C.free(unsafe.Pointer(cPass))
-	//cRetValue = C.k2h_get_str_direct_value(k2h.handle, (*C.char)(cKey))
-	ok := C.k2h_get_str_value_wp(k2h.handle, (*C.char)(cKey), &cRetValue, cPass)
+	var cRetValue *C.uchar
+	var cRetValueLen C.size_t
+	ok, errno := C.k2h_get_value_wp(k2h.handle, cKey, cKeyLen, &cRetValue, &cRetValueLen, cPass)
+	if ok != true && k2h.retryDecrypt(key, &params) {
+		C.free(unsafe.Pointer(cRetValue))
+		cRetValue, cRetValueLen = nil, 0
+		ok, errno = C.k2h_get_value_wp(k2h.handle, cKey, cKeyLen, &cRetValue, &cRetValueLen, cPass)
+	}
+	defer C.free(unsafe.Pointer(cRetValue))
 	if ok != true {
-		return nil, fmt.Errorf("C.k2h_get_str_value return false")
+		return dst, k2h.valueError(op, key, errno)
 	}
-	rv := C.GoString(cRetValue)
-	r := &GetResult{
-		val: []byte(rv),
+	if cRetValue == nil || cRetValueLen == 0 {
+		return dst, nil
 	}
-	return r, nil
+	return append(dst, unsafe.Slice((*byte)(unsafe.Pointer(cRetValue)), int(cRetValueLen))...), nil
 }
 
 // Local Variables:
@@ -25,57 +25,185 @@ import (
 )
 
 import (
+	"encoding/binary"
 	"fmt"
+	"time"
 	"unsafe"
 )
 
+const (
+	// attrEncrypt is the attribute name of the encrypted value set by the builtin attribute plugin.
+	attrEncrypt = "encrypt"
+	// attrMtime is the attribute name of the modification time set by the builtin attribute plugin.
+	attrMtime = "mtime"
+	// attrExpire is the attribute name of the expiration time set by the builtin attribute plugin.
+	attrExpire = "expire"
+	// attrHistory is the attribute name of the history set by the builtin attribute plugin.
+	attrHistory = "history"
+)
+
+// isBuiltinAttr returns true if the attribute is maintained by the builtin attribute plugin.
+func isBuiltinAttr(name string) bool {
+	switch name {
+	case attrEncrypt, attrMtime, attrExpire, attrHistory:
+		return true
+	default:
+		return false
+	}
+}
+
 // Attr holds attribute names and values.
 type Attr struct {
-	key string
-	val string
+	name string // without a null termination
+	val  []byte // raw bytes
+}
+
+// NewAttr returns a new attribute. The name is saved with a null termination in the same way as AddAttr,
+// and the value is saved as it is.
+func NewAttr(name string, value []byte) Attr {
+	return Attr{name: name, val: value}
+}
+
+// Name returns the attribute name without the null termination.
+func (r *Attr) Name() string {
+	return r.name
+}
+
+// Value returns the attribute value as it is saved. A value saved as a string contains the null termination.
+func (r *Attr) Value() []byte {
+	return r.val
 }
 
 // String returns a text representation of the object.
 func (r *Attr) String() string {
-	return fmt.Sprintf("[%v, %v]", r.key, r.val)
+	return fmt.Sprintf("[%v, %q]", r.name, trimNull(r.val))
+}
+
+// Attrs is a list of attributes of a key.
+type Attrs []Attr
+
+// Get returns the value of the attribute name.
+func (a Attrs) Get(name string) ([]byte, bool) {
+	for i := range a {
+		if a[i].name == name {
+			return a[i].val, true
+		}
+	}
+	return nil, false
 }
 
-// GetAttrs returns a slice of string.
-func (k2h *K2hash) GetAttrs(k string) ([]Attr, error) {
-	// 1. retrieve an attribute using k2h_get_attrs
+// Mtime returns the modification time set by the builtin attribute plugin. It is zero if mtime is disabled.
+func (a Attrs) Mtime() time.Time {
+	val, _ := a.Get(attrMtime)
+	return decodeAttrTime(val)
+}
+
+// Expire returns the expiration time set by the builtin attribute plugin. It is zero if the value never expires.
+func (a Attrs) Expire() time.Time {
+	val, _ := a.Get(attrExpire)
+	return decodeAttrTime(val)
+}
+
+// HistoryUUID returns the UUID of the history set by the builtin attribute plugin in text format.
+// It is empty if history is disabled.
+func (a Attrs) HistoryUUID() string {
+	val, ok := a.Get(attrHistory)
+	if !ok {
+		return ""
+	}
+	return formatUUID(val)
+}
+
+// formatUUID returns a UUID in text format. A binary UUID is formatted in the canonical form.
+func formatUUID(b []byte) string {
+	if len(b) == 16 {
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+	}
+	return string(trimNull(b))
+}
+
+// IsEncrypted returns true if the value is encrypted by the builtin attribute plugin.
+func (a Attrs) IsEncrypted() bool {
+	_, ok := a.Get(attrEncrypt)
+	return ok
+}
+
+// decodeAttrTime decodes a time saved by the builtin attribute plugin, which is either a struct timespec
+// or a time_t in the host byte order. It returns the zero time if the format is unknown.
+func decodeAttrTime(b []byte) time.Time {
+	switch {
+	case len(b) >= 16:
+		sec := int64(binary.NativeEndian.Uint64(b[0:8]))
+		nsec := int64(binary.NativeEndian.Uint64(b[8:16]))
+		return time.Unix(sec, nsec)
+	case len(b) == 8:
+		return time.Unix(int64(binary.NativeEndian.Uint64(b)), 0)
+	default:
+		return time.Time{}
+	}
+}
+
+// GetAttrs returns the attributes of a key. The attribute values are returned as they are saved.
+func (k2h *K2hash) GetAttrs(k interface{}) (Attrs, error) {
+	// 1. binary or text
+	key, err := toBytes(k)
+	if err != nil {
+		return Attrs{}, err
+	}
+	if err := k2h.checkOpen("GetAttrs", key); err != nil {
+		return Attrs{}, err
+	}
+
+	// 2. retrieve an attribute using k2h_get_attrs
 	// bool k2h_get_attrs(k2h_h handle, const unsigned char* pkey, size_t keylength, PK2HATTRPCK* ppattrspck, int* pattrspckcnt)
-	cKey := C.CBytes([]byte(k))
+	cKey, cKeyLen := cBytes(key)
 	defer C.free(unsafe.Pointer(cKey))
 	var attrpack C.PK2HATTRPCK
 	var attrpackCnt C.int
-	ok := C.k2h_get_attrs(
+	ok, errno := C.k2h_get_attrs(
 		k2h.handle,
-		(*C.uchar)(cKey),
-		C.size_t(len([]byte(k))+1), // plus one for a null termination
+		cKey,
+		cKeyLen,
 		&attrpack,
 		&attrpackCnt,
 	)
 	defer C.k2h_free_attrpack(attrpack, attrpackCnt) // free the memory for the keypack for myself(GC doesn't know the area)
 
 	if ok == false {
-		return []Attr{}, fmt.Errorf("C.k2h_get_attrs() = %v", ok)
+		return Attrs{}, k2h.keyError("GetAttrs", key, errno)
 	} else if attrpackCnt == 0 {
-		return []Attr{}, nil
+		return Attrs{}, nil
+	}
+	// 3. copy an attribute data to a slice
+	return goAttrs(attrpack, attrpackCnt), nil
+}
+
+// goAttrs copies attributes in a K2HATTRPCK array to Attrs. It never returns nil.
+func goAttrs(pack C.PK2HATTRPCK, count C.int) Attrs {
+	names, vals := goAttrPack(pack, count)
+	attrs := make(Attrs, len(names))
+	for i := range names {
+		// exclude a null termination from the name only.
+		attrs[i].name = string(trimNull(names[i]))
+		attrs[i].val = vals[i]
+	}
+	return attrs
+}
+
+// goAttrPack copies names and values in a K2HATTRPCK array to slices of byte sequences.
+func goAttrPack(pack C.PK2HATTRPCK, count C.int) ([][]byte, [][]byte) {
+	if pack == nil || count <= 0 {
+		return [][]byte{}, [][]byte{}
 	}
-	// 2. copy an attribute data to a slice
-	var CAttrs C.PK2HATTRPCK = attrpack
-	count := (int)(attrpackCnt)
-	slice := (*[1 << 28]C.K2HATTRPCK)(unsafe.Pointer(CAttrs))[:count:count]
-	attrs := make([]Attr, count) // copy
+	length := int(count)
+	slice := (*[1 << 28]C.K2HATTRPCK)(unsafe.Pointer(pack))[:length:length]
+	names := make([][]byte, length)
+	vals := make([][]byte, length)
 	for i, data := range slice {
-		// copy the data with len-1 length, which exclude a null termination.
-		attrkey := C.GoBytes(unsafe.Pointer(data.pkey), (C.int)(data.keylength-1))
-		attrval := C.GoBytes(unsafe.Pointer(data.pval), (C.int)(data.vallength-1))
-		// cast bytes to a string
-		attrs[i].key = string(attrkey)
-		attrs[i].val = string(attrval)
+		names[i] = goBytes(data.pkey, data.keylength)
+		vals[i] = goBytes(data.pval, data.vallength)
 	}
-	return attrs, nil
+	return names, vals
 }
 
 // Local Variables:
@@ -26,42 +26,100 @@ import (
 
 import (
 	"fmt"
+	"iter"
 	"unsafe"
 )
 
-// GetSubKeys returns subkeys to a key.
-func (k2h *K2hash) GetSubKeys(k string) ([]string, error) {
-	// 1. retrieve subkeys using k2h_get_subkeys
-	cKey := C.CBytes([]byte(k))
+// GetSubKeys returns subkeys to a key. It returns an empty slice if the key has no subkeys, and nil on errors.
+func (k2h *K2hash) GetSubKeys(k interface{}) ([]string, error) {
+	// 1. binary or text
+	key, err := toBytes(k)
+	if err != nil {
+		return nil, err
+	}
+	if err := k2h.checkOpen("GetSubKeys", key); err != nil {
+		return nil, err
+	}
+
+	// 2. retrieve subkeys and exclude a null termination.
+	skeys, err := k2h.subKeys("GetSubKeys", key)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]string, len(skeys))
+	for i, sk := range skeys {
+		ret[i] = string(trimNull(sk))
+	}
+	return ret, nil
+}
+
+// SubKey is a subkey yielded by SubKeys.
+type SubKey struct {
+	// Key is the subkey. A key saved as a string contains the null termination.
+	Key []byte
+	// Value is the value of the subkey. A value saved as a string contains the null termination.
+	Value []byte
+	// Attrs is the attributes of the subkey.
+	Attrs Attrs
+}
+
+// String returns a text representation of the object.
+func (s SubKey) String() string {
+	return fmt.Sprintf("[%q, %q, %v]", trimNull(s.Key), trimNull(s.Value), s.Attrs)
+}
+
+// SubKeys returns an iterator over the subkeys to a key with their values and attributes.
+// An error of a subkey, like ErrNotFound for a subkey which doesn't exist, is yielded with the subkey
+// and the iteration continues. An error of the key is yielded once with a zero SubKey.
+func (k2h *K2hash) SubKeys(k interface{}, options ...func(*Params)) iter.Seq2[SubKey, error] {
+	return func(yield func(SubKey, error) bool) {
+		// 1. binary or text
+		key, err := toBytes(k)
+		if err != nil {
+			yield(SubKey{}, err)
+			return
+		}
+		if err := k2h.checkOpen("SubKeys", key); err != nil {
+			yield(SubKey{}, err)
+			return
+		}
+		skeys, err := k2h.subKeys("SubKeys", key)
+		if err != nil {
+			yield(SubKey{}, err)
+			return
+		}
+		// 2. read a subkey at a time, so that breaking the loop stops reading
+		for _, sk := range skeys {
+			e, err := k2h.GetEntry(sk, options...)
+			if err != nil {
+				if !yield(SubKey{Key: sk}, err) {
+					return
+				}
+				continue
+			}
+			if !yield(SubKey{Key: sk, Value: e.Value, Attrs: e.Attrs}, nil) {
+				return
+			}
+		}
+	}
+}
+
+// subKeys returns subkeys to a key in binary format. It returns no subkeys if k2h_get_subkeys fails
+// for an existing key, because it fails if the key has no subkeys.
+func (k2h *K2hash) subKeys(op string, key []byte) ([][]byte, error) {
+	cKey, cKeyLen := cBytes(key)
 	defer C.free(unsafe.Pointer(cKey))
 	var keypack C.PK2HKEYPCK
 	var keypackLen C.int
-	ok := C.k2h_get_subkeys(
-		k2h.handle,
-		(*C.uchar)(cKey),
-		C.size_t(len([]byte(k))+1), // plus one for a null termination
-		&keypack,
-		&keypackLen,
-	)
-	defer C.k2h_free_keypack(keypack, keypackLen) // free the memory for the keypack for myself(GC doesn't know the area)
-
-	if ok == false {
-		return []string{""}, fmt.Errorf("C.k2h_get_subkeys() = %v", ok)
-	} else if keypackLen == 0 {
-		return []string{""}, nil
-	}
-	// 2. copy a subkey data to a slice
-	var CSubKey C.PK2HKEYPCK = keypack
-	length := (int)(keypackLen)
-	slice := (*[1 << 28]C.K2HKEYPCK)(unsafe.Pointer(CSubKey))[:length:length]
-	skeys := make([]string, length) // copy
-	for i, data := range slice {
-		// copy the data with len-1 length, which exclude a null termination.
-		sk := C.GoBytes(unsafe.Pointer(data.pkey), (C.int)(data.length-1))
-		// cast byte array to string
-		skeys[i] = string(sk)
+	ok, errno := C.k2h_get_subkeys(k2h.handle, cKey, cKeyLen, &keypack, &keypackLen)
+	defer C.k2h_free_keypack(keypack, keypackLen)
+	if !ok {
+		if k2h.exists(key) {
+			return [][]byte{}, nil
+		}
+		return nil, newOpError(op, key, errno, ErrNotFound)
 	}
-	return skeys, nil
+	return goKeys(keypack, keypackLen), nil
 }
 
 // Local Variables:
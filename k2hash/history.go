@@ -0,0 +1,113 @@
+//
+// k2hash_go
+//
+// Copyright 2018 Yahoo Japan Corporation.
+//
+// Go driver for k2hash that is a NoSQL Key Value Store(KVS) library.
+// For k2hash, see https://github.com/yahoojapan/k2hash for the details.
+//
+// For the full copyright and license information, please view
+// the license file that was distributed with this source code.
+//
+// AUTHOR:   Hirotaka Wakabayashi
+// CREATE:   Sun, 18 Oct 2026
+// REVISION:
+//
+
+package k2hash
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Version holds an earlier value of a key kept by the history attribute.
+type Version struct {
+	// UUID is the UUID of the history key in text format.
+	UUID string
+	// Value is the value in binary format. A value saved as a string contains the null termination.
+	Value []byte
+	// Mtime is the modification time of the value. It is zero if mtime was disabled.
+	Mtime time.Time
+}
+
+// String returns a text representation of the object.
+func (v *Version) String() string {
+	return fmt.Sprintf("[%v, %v, %v]", v.UUID, v.Value, v.Mtime)
+}
+
+// History returns the earlier values of a key from the newest one, which are kept by the builtin attribute
+// plugin while history is enabled by EnableHistory. The history attribute of a key is the name of the key
+// which keeps the previous value, and that key has the history attribute of the value it replaced, so that
+// the versions are chained. WithPassword is used to decrypt encrypted values.
+func (k2h *K2hash) History(k interface{}, options ...func(*Params)) ([]Version, error) {
+	e, err := k2h.GetEntry(k, options...)
+	if err != nil {
+		return nil, err
+	}
+	return k2h.history(e, options...)
+}
+
+// GetAsOf returns the value of a key as it was at t, which is the newest version modified at or before t.
+// It requires the mtime attribute enabled by EnableMtime. It returns ErrNotFound if no version exists at t.
+func (k2h *K2hash) GetAsOf(k interface{}, t time.Time, options ...func(*Params)) (*GetResult, error) {
+	key, err := toBytes(k)
+	if err != nil {
+		return nil, err
+	}
+	e, err := k2h.GetEntry(key, options...)
+	if err != nil {
+		return nil, err
+	}
+	if e.Mtime.IsZero() {
+		return nil, newOpError("GetAsOf", key, nil, fmt.Errorf("%w: mtime attribute is disabled", ErrInvalid))
+	}
+	if !e.Mtime.After(t) {
+		return &GetResult{val: e.Value}, nil
+	}
+	versions, err := k2h.history(e, options...)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range versions {
+		if !v.Mtime.IsZero() && !v.Mtime.After(t) {
+			return &GetResult{val: v.Value}, nil
+		}
+	}
+	return nil, newOpError("GetAsOf", key, nil, ErrNotFound)
+}
+
+// history follows the history attributes from an entry.
+func (k2h *K2hash) history(e *Entry, options ...func(*Params)) ([]Version, error) {
+	versions := []Version{}
+	seen := make(map[string]bool)
+	for {
+		id, ok := e.Attrs.Get(attrHistory)
+		if !ok || len(id) == 0 || seen[string(id)] {
+			return versions, nil
+		}
+		seen[string(id)] = true
+		prev, err := k2h.GetEntry(id, options...)
+		if errors.Is(err, ErrNotFound) {
+			// The history may be removed.
+			return versions, nil
+		} else if err != nil {
+			return nil, err
+		}
+		versions = append(versions, Version{
+			UUID:  formatUUID(id),
+			Value: prev.Value,
+			Mtime: prev.Mtime,
+		})
+		e = prev
+	}
+}
+
+// Local Variables:
+// c-basic-offset: 4
+// tab-width: 4
+// indent-tabs-mode: t
+// End:
+// vim600: noexpandtab sw=4 ts=4 fdm=marker
+// vim<600: noexpandtab sw=4 ts=4
@@ -0,0 +1,215 @@
+//
+// k2hash_go
+//
+// Copyright 2018 Yahoo Japan Corporation.
+//
+// Go driver for k2hash that is a NoSQL Key Value Store(KVS) library.
+// For k2hash, see https://github.com/yahoojapan/k2hash for the details.
+//
+// For the full copyright and license information, please view
+// the license file that was distributed with this source code.
+//
+// AUTHOR:   Hirotaka Wakabayashi
+// CREATE:   Sun, 18 Oct 2026
+// REVISION:
+//
+
+package k2hash
+
+import (
+	// #cgo CFLAGS: -g -O2 -Wall -Wextra -Wno-unused-variable -Wno-unused-parameter -I. -I/usr/include/k2hash
+	// #cgo LDFLAGS: -L/usr/lib -lk2hash
+	// #include <stdlib.h>
+	// #include "k2hash.h"
+	"C"
+)
+
+import (
+	"fmt"
+	"iter"
+	"unsafe"
+)
+
+// Cursor walks all keys in a k2hash file by using a k2hash find handle.
+// A Cursor must be closed by Close if Next doesn't return false.
+type Cursor struct {
+	// k2h is the k2hash file.
+	k2h *K2hash
+	// fhandle is a k2hash find handle.
+	fhandle C.k2h_find_h
+	// started is true after the first call of Next.
+	started bool
+	// pos is the number of keys walked including the staging keys.
+	pos uint64
+	// key is the current key.
+	key []byte
+	// val is the current value, which is read lazily.
+	val []byte
+	// err is the first error while walking.
+	err error
+}
+
+// String returns a text representation of the object.
+func (c *Cursor) String() string {
+	return fmt.Sprintf("[%v, %v, %v]", c.fhandle, c.started, c.key)
+}
+
+// NewCursor returns a new cursor which walks all keys in the k2hash file.
+func (k2h *K2hash) NewCursor() (*Cursor, error) {
+	if err := k2h.checkOpen("NewCursor", nil); err != nil {
+		return nil, err
+	}
+	c := Cursor{
+		k2h:     k2h,
+		fhandle: C.K2H_INVALID_HANDLE,
+	}
+	return &c, nil
+}
+
+// Next moves the cursor to the next key. It returns false if no more keys exist or an error occurs.
+// The staging keys of SetEntry are skipped.
+func (c *Cursor) Next() bool {
+	if c.err != nil {
+		return false
+	}
+	c.key = nil
+	c.val = nil
+	for {
+		if !c.started {
+			c.started = true
+			if err := c.k2h.checkOpen("Cursor.Next", nil); err != nil {
+				c.err = err
+				return false
+			}
+			c.fhandle = C.k2h_find_first(c.k2h.handle)
+		} else if c.fhandle != C.K2H_INVALID_HANDLE {
+			// k2h_find_next returns K2H_INVALID_HANDLE at the end of keys.
+			c.fhandle = C.k2h_find_next(c.fhandle)
+		}
+		if c.fhandle == C.K2H_INVALID_HANDLE {
+			return false
+		}
+		c.pos++
+		var cKey *C.uchar
+		var cKeyLen C.size_t
+		ok, errno := C.k2h_find_get_key(c.fhandle, &cKey, &cKeyLen)
+		key := goBytes(cKey, cKeyLen)
+		C.free(unsafe.Pointer(cKey))
+		if ok != true {
+			c.err = newOpError("Cursor.Next", nil, errno, nil)
+			c.Close()
+			return false
+		}
+		if !isStagingKey(key) {
+			c.key = key
+			return true
+		}
+	}
+}
+
+// Key returns the current key. A key saved as a string contains the null termination.
+func (c *Cursor) Key() []byte {
+	return c.key
+}
+
+// Value returns the value of the current key.
+func (c *Cursor) Value() ([]byte, error) {
+	if c.key == nil {
+		return nil, newOpError("Cursor.Value", nil, nil, ErrNotFound)
+	}
+	if c.val != nil {
+		return c.val, nil
+	}
+	var cVal *C.uchar
+	var cValLen C.size_t
+	ok, errno := C.k2h_find_get_value(c.fhandle, &cVal, &cValLen)
+	defer C.free(unsafe.Pointer(cVal))
+	params := Params{
+		password:           "",
+		expirationDuration: 0,
+	}
+	if ok != true && c.k2h.retryDecrypt(c.key, &params) {
+		// A value encrypted with a new passphrase is read by the key after the passphrases are reloaded.
+		val, err := c.k2h.getInto("Cursor.Value", c.key, []byte{})
+		if err != nil {
+			return nil, err
+		}
+		c.val = val
+		return c.val, nil
+	}
+	if ok != true {
+		return nil, newOpError("Cursor.Value", c.key, errno, nil)
+	}
+	c.val = goBytes(cVal, cValLen)
+	return c.val, nil
+}
+
+// Err returns the first error while walking.
+func (c *Cursor) Err() error {
+	return c.err
+}
+
+// Close frees the k2hash find handle. It is safe to call Close more than once.
+func (c *Cursor) Close() error {
+	c.started = true
+	if c.fhandle == C.K2H_INVALID_HANDLE {
+		return nil
+	}
+	ok, errno := C.k2h_find_free(c.fhandle)
+	c.fhandle = C.K2H_INVALID_HANDLE
+	if ok != true {
+		return newOpError("Cursor.Close", nil, errno, nil)
+	}
+	return nil
+}
+
+// Iterate calls fn with each key and value in the k2hash file until fn returns an error.
+// It returns the error returned by fn or an error while walking.
+func (k2h *K2hash) Iterate(fn func(key, val []byte) error) error {
+	c, err := k2h.NewCursor()
+	if err != nil {
+		return err
+	}
+	// Close frees the find handle even if fn panics.
+	defer c.Close()
+	for c.Next() {
+		val, err := c.Value()
+		if err != nil {
+			return err
+		}
+		if err := fn(c.Key(), val); err != nil {
+			return err
+		}
+	}
+	return c.Err()
+}
+
+// Keys returns an iterator over all keys and values in the k2hash file.
+// The iteration stops silently on errors. Use Iterate or NewCursor to check errors.
+func (k2h *K2hash) Keys() iter.Seq2[[]byte, []byte] {
+	return func(yield func([]byte, []byte) bool) {
+		c, err := k2h.NewCursor()
+		if err != nil {
+			return
+		}
+		// Close frees the find handle even if the loop body breaks or panics.
+		defer c.Close()
+		for c.Next() {
+			val, err := c.Value()
+			if err != nil {
+				return
+			}
+			if !yield(c.Key(), val) {
+				return
+			}
+		}
+	}
+}
+
+// Local Variables:
+// c-basic-offset: 4
+// tab-width: 4
+// indent-tabs-mode: t
+// End:
+// vim600: noexpandtab sw=4 ts=4 fdm=marker
+// vim<600: noexpandtab sw=4 ts=4
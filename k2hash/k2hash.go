@@ -25,7 +25,12 @@ import (
 )
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
 	"unsafe"
 )
 
@@ -33,6 +38,7 @@ import (
 type Params struct {
 	password           string
 	expirationDuration int64
+	replaceAttrs       bool
 }
 
 // QueueParams stores parameters for k2hash queue C API.
@@ -44,13 +50,39 @@ type QueueParams struct {
 
 // String returns a text representation of the object.
 func (p *Params) String() string {
-	return fmt.Sprintf("[%v, %v]", p.password, p.expirationDuration)
+	return fmt.Sprintf("[%v, %v, %v]", p.password, p.expirationDuration, p.replaceAttrs)
+}
+
+// openMode is a type of k2hash databases.
+type openMode int
+
+const (
+	// modeFile opens a k2hash file.
+	modeFile openMode = iota
+	// modeMemory opens a memory only k2hash database.
+	modeMemory
+	// modeTemp opens a temporary k2hash file, which is removed when it is closed.
+	modeTemp
+)
+
+// String returns a text representation of the object.
+func (m openMode) String() string {
+	switch m {
+	case modeMemory:
+		return "memory"
+	case modeTemp:
+		return "temp"
+	default:
+		return "file"
+	}
 }
 
 // K2hash keeps configurations, and it is responsible for creating request handles with a k2hash database files and closing them.
 type K2hash struct {
 	// filepath is a path to K2HASH file.
 	filepath string
+	// mode is a type of the database. default is modeFile.
+	mode openMode
 	// readonly enables read only file access if true. default is false.
 	readonly bool
 	// removefile enables automatic file deletion if no process attaches the file. default is false.
@@ -67,30 +99,94 @@ type K2hash struct {
 	pagesize int
 	// waitms is a time to wait until a transaction is completed. default is -1.
 	waitms int
+	// tempdir is the directory of a temporary file created by NewTempK2hash, which is removed on Close.
+	tempdir string
 	// handle is a file descriptor to a K2HASH file.
 	handle C.k2h_h
+	// pool is a pool of buffers for values got by Get. default is nil.
+	pool *BufferPool
+	// keyProvider provides the passphrases of the encryption attribute. default is nil.
+	keyProvider KeyProvider
+	// keyReloadInterval is the minimum interval of reloads on decrypt failures. default is 1s.
+	keyReloadInterval time.Duration
+	// keys is the state of the passphrases passed to libk2hash by the key provider.
+	keys keyState
+	// sweepInterval is the interval of the expired key sweeper. default is 0, which disables it.
+	sweepInterval time.Duration
+	// sweepRate is the max number of keys the sweeper handles per second. default is 0, which means no limit.
+	sweepRate int
+	// sweep is the state of the expired key sweeper.
+	sweep sweeper
+	// inflight counts operations abandoned by the context variants which are still running.
+	inflight inflightOps
+	// scans keeps the find handles of ScanCursor between pages.
+	scans scanParking
+	// entries serializes the rewrites of a key through its staging key.
+	entries entryLocks
+	// defaultExpiration is the default expiration duration in seconds set by SetExpirationDuration.
+	defaultExpiration atomic.Int64
 }
 
 // String returns a text representation of the object.
 func (k2h *K2hash) String() string {
-	return fmt.Sprintf("[%v, %v, %v, %v, %v, %v, %v, %v, %v, %v]",
-		k2h.filepath, k2h.readonly, k2h.removefile, k2h.fullmap, k2h.maskbitcnt, k2h.cmaskbitcnt, k2h.maxelementcnt, k2h.pagesize, k2h.waitms, k2h.handle)
+	return fmt.Sprintf("[%v, %v, %v, %v, %v, %v, %v, %v, %v, %v, %v]",
+		k2h.filepath, k2h.mode, k2h.readonly, k2h.removefile, k2h.fullmap, k2h.maskbitcnt, k2h.cmaskbitcnt, k2h.maxelementcnt, k2h.pagesize, k2h.waitms, k2h.handle)
 }
 
 // NewK2hash returns a new k2hash instance.
 func NewK2hash(f string, options ...func(*K2hash)) (*K2hash, error) {
+	return newK2hash(f, modeFile, options...)
+}
+
+// NewMemoryK2hash returns a new k2hash instance of a memory only database.
+// The data is lost when it is closed.
+func NewMemoryK2hash(options ...func(*K2hash)) (*K2hash, error) {
+	return newK2hash("", modeMemory, options...)
+}
+
+// NewTempK2hash returns a new k2hash instance of a temporary file, which is removed when it is closed.
+// If f is empty, the file is created in a new private directory in os.TempDir(), which is also removed.
+func NewTempK2hash(f string, options ...func(*K2hash)) (*K2hash, error) {
+	if f != "" {
+		return newK2hash(f, modeTemp, options...)
+	}
+	// k2h_open_tempfile creates the file by itself, so that the name must be unique in a directory
+	// no other users can write.
+	dir, err := os.MkdirTemp("", "k2hash_")
+	if err != nil {
+		return nil, err
+	}
+	options = append(options[:len(options):len(options)], func(k2h *K2hash) {
+		k2h.tempdir = dir
+	})
+	k2h, err := newK2hash(filepath.Join(dir, "temp.k2h"), modeTemp, options...)
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	return k2h, nil
+}
+
+// newK2hash returns a new k2hash instance of the mode.
+func newK2hash(f string, mode openMode, options ...func(*K2hash)) (*K2hash, error) {
 	// 1. set defaults
 	k2h := K2hash{
-		filepath:      f,
-		readonly:      false,
-		removefile:    false,
-		fullmap:       false,
-		maskbitcnt:    8,
-		cmaskbitcnt:   4,
-		maxelementcnt: 1024,
-		pagesize:      512,
-		waitms:        0,
-		handle:        0,
+		filepath:          f,
+		mode:              mode,
+		readonly:          false,
+		removefile:        false,
+		fullmap:           false,
+		maskbitcnt:        8,
+		cmaskbitcnt:       4,
+		maxelementcnt:     1024,
+		pagesize:          512,
+		waitms:            0,
+		handle:            0,
+		pool:              nil,
+		keyProvider:       nil,
+		keyReloadInterval: defaultKeyReloadInterval,
+		sweepInterval:     0,
+		sweepRate:         0,
 	}
 	// 2. set options
 	for _, option := range options {
@@ -117,31 +213,106 @@ func (k2h *K2hash) Create() bool {
 
 // Open opens a k2hash file.
 func (k2h *K2hash) Open() (bool, error) {
+	if err := k2h.validate(); err != nil {
+		return false, err
+	}
 	cK2h := C.CString(k2h.filepath)
 	defer C.free(unsafe.Pointer(cK2h))
-	k2h.handle = C.k2h_open(
-		cK2h,
-		C._Bool(k2h.readonly),
-		C._Bool(k2h.removefile),
-		C._Bool(k2h.fullmap),
-		C.int(k2h.maskbitcnt),
-		C.int(k2h.cmaskbitcnt),
-		C.int(k2h.maxelementcnt),
-		C.size_t(k2h.pagesize))
-
-	if k2h.handle == C.K2H_INVALID_HANDLE {
-		return false, fmt.Errorf("k2h_open() returns K2H_INVALID_HANDLE(%v)", k2h.handle)
+	var handle C.k2h_h
+	var errno error
+	switch k2h.mode {
+	case modeMemory:
+		handle, errno = C.k2h_open_mem(
+			C.int(k2h.maskbitcnt),
+			C.int(k2h.cmaskbitcnt),
+			C.int(k2h.maxelementcnt),
+			C.size_t(k2h.pagesize))
+	case modeTemp:
+		handle, errno = C.k2h_open_tempfile(
+			cK2h,
+			C._Bool(k2h.fullmap),
+			C.int(k2h.maskbitcnt),
+			C.int(k2h.cmaskbitcnt),
+			C.int(k2h.maxelementcnt),
+			C.size_t(k2h.pagesize))
+	default:
+		handle, errno = C.k2h_open(
+			cK2h,
+			C._Bool(k2h.readonly),
+			C._Bool(k2h.removefile),
+			C._Bool(k2h.fullmap),
+			C.int(k2h.maskbitcnt),
+			C.int(k2h.cmaskbitcnt),
+			C.int(k2h.maxelementcnt),
+			C.size_t(k2h.pagesize))
+	}
+
+	if handle == C.K2H_INVALID_HANDLE {
+		return false, newOpError("Open", nil, errno, nil)
+	}
+	k2h.handle = handle
+	if k2h.keyProvider != nil {
+		if _, err := k2h.ReloadKeys(context.Background()); err != nil {
+			C.k2h_close(k2h.handle)
+			k2h.handle = C.K2H_INVALID_HANDLE
+			return false, err
+		}
+	}
+	if k2h.sweepInterval > 0 {
+		k2h.startSweeper()
 	}
 	return true, nil
 }
 
 // Close closes a k2hash file.
 func (k2h *K2hash) Close() (bool, error) {
-	ok := C.k2h_close_wait(k2h.handle, C.long(k2h.waitms))
+	return k2h.CloseContext(context.Background())
+}
+
+// CloseContext closes a k2hash file. It waits for the operations abandoned by the context variants,
+// and the time to wait until a transaction is completed is bounded by the deadline of ctx.
+// The file is not closed if ctx is done.
+func (k2h *K2hash) CloseContext(ctx context.Context) (bool, error) {
+	if err := k2h.checkOpen("Close", nil); err != nil {
+		return false, err
+	}
+	// 1. stop the sweeper and wait for abandoned operations
+	if err := k2h.stopSweeper(ctx); err != nil {
+		return false, newOpError("Close", nil, nil, err)
+	}
+	done := make(chan struct{})
+	go func() {
+		k2h.inflight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return false, newOpError("Close", nil, nil, ctx.Err())
+	}
+	// 2. close
+	k2h.scans.closeAll()
+	waitms := k2h.waitms
+	if deadline, ok := ctx.Deadline(); ok {
+		remaining := int(time.Until(deadline).Milliseconds())
+		if remaining < 0 {
+			remaining = 0
+		}
+		if waitms < 0 || remaining < waitms {
+			waitms = remaining
+		}
+	}
+	ok, errno := C.k2h_close_wait(k2h.handle, C.long(waitms))
 	if ok != true {
-		return false, fmt.Errorf("k2h_close() returns false")
+		return false, newOpError("Close", nil, errno, ctx.Err())
 	}
 	k2h.handle = C.K2H_INVALID_HANDLE
+	if k2h.tempdir != "" {
+		if err := os.RemoveAll(k2h.tempdir); err != nil {
+			return false, newOpError("Close", nil, nil, err)
+		}
+		k2h.tempdir = ""
+	}
 	return true, nil
 }
 
@@ -0,0 +1,236 @@
+//
+// k2hash_go
+//
+// Copyright 2018 Yahoo Japan Corporation.
+//
+// Go driver for k2hash that is a NoSQL Key Value Store(KVS) library.
+// For k2hash, see https://github.com/yahoojapan/k2hash for the details.
+//
+// For the full copyright and license information, please view
+// the license file that was distributed with this source code.
+//
+// AUTHOR:   Hirotaka Wakabayashi
+// CREATE:   Sun, 18 Oct 2026
+// REVISION:
+//
+
+package k2hash
+
+import (
+	// #cgo CFLAGS: -g -O2 -Wall -Wextra -Wno-unused-variable -Wno-unused-parameter -I. -I/usr/include/k2hash
+	// #cgo LDFLAGS: -L/usr/lib -lk2hash
+	// #include <stdlib.h>
+	// #include "k2hash.h"
+	"C"
+)
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// defaultKeyReloadInterval is the default minimum interval of reloads on decrypt failures.
+const defaultKeyReloadInterval = time.Second
+
+// KeyProvider provides the passphrases of the encryption attribute. A handle opened with WithKeyProvider asks
+// it for the passphrases at open time and again when a value can't be decrypted, so that the handle doesn't
+// keep them in Go strings. The passphrases are cleared after they are passed to libk2hash.
+type KeyProvider interface {
+	// Passphrases returns the passphrases. The first one is the default passphrase for encryption, and the
+	// rest are used only for decryption.
+	Passphrases(ctx context.Context) ([][]byte, error)
+}
+
+// EnvKeyProvider is a KeyProvider which reads the passphrases from the environment variables in order.
+// Empty or unset variables are ignored. The passphrases are kept in the environment of the process and in
+// the Go strings returned by os.Getenv, which are immutable and can't be cleared. Use FileKeyProvider or
+// your own KeyProvider if the passphrases must not stay in memory.
+type EnvKeyProvider []string
+
+// Passphrases returns the values of the environment variables.
+func (p EnvKeyProvider) Passphrases(ctx context.Context) ([][]byte, error) {
+	var passes [][]byte
+	for _, name := range p {
+		if val := os.Getenv(name); val != "" {
+			passes = append(passes, []byte(val))
+		}
+	}
+	if len(passes) == 0 {
+		return nil, fmt.Errorf("%w: no passphrase in environment variables %v", ErrInvalid, []string(p))
+	}
+	return passes, nil
+}
+
+// FileKeyProvider is a KeyProvider which reads the passphrases from the file, one passphrase per line,
+// like the passphrase file of EnableEncryption. Empty lines are ignored.
+type FileKeyProvider string
+
+// Passphrases returns the lines of the file.
+func (p FileKeyProvider) Passphrases(ctx context.Context) ([][]byte, error) {
+	data, err := os.ReadFile(string(p))
+	if err != nil {
+		return nil, err
+	}
+	defer clear(data)
+	var passes [][]byte
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if line = bytes.TrimRight(line, "\r"); len(line) > 0 {
+			passes = append(passes, bytes.Clone(line))
+		}
+	}
+	if len(passes) == 0 {
+		return nil, fmt.Errorf("%w: no passphrase in file %v", ErrInvalid, string(p))
+	}
+	return passes, nil
+}
+
+// KeyProviderFunc is an adapter to use a function as a KeyProvider.
+type KeyProviderFunc func(ctx context.Context) ([][]byte, error)
+
+// Passphrases calls f(ctx).
+func (f KeyProviderFunc) Passphrases(ctx context.Context) ([][]byte, error) {
+	return f(ctx)
+}
+
+// keyState keeps salted digests of the passphrases passed to libk2hash, so that a passphrase isn't passed
+// twice without keeping the passphrase itself.
+type keyState struct {
+	// mu serializes reloads.
+	mu sync.Mutex
+	// salt is the random salt of the digests.
+	salt []byte
+	// added is the set of the digests of the passphrases passed to libk2hash.
+	added map[[sha256.Size]byte]bool
+	// current is the digest of the default passphrase.
+	current [sha256.Size]byte
+	// next is the time when the next reload on a decrypt failure is allowed.
+	next time.Time
+}
+
+// digest returns the salted digest of a passphrase.
+func (s *keyState) digest(pass []byte) [sha256.Size]byte {
+	if s.salt == nil {
+		s.salt = make([]byte, 16)
+		rand.Read(s.salt)
+	}
+	h := sha256.New()
+	h.Write(s.salt)
+	h.Write(pass)
+	var d [sha256.Size]byte
+	h.Sum(d[:0])
+	return d
+}
+
+// ReloadKeys asks the KeyProvider given by WithKeyProvider for the passphrases and passes them to libk2hash.
+// It is called by Open, and by Get, GetInto and GetEntry when a value can't be decrypted, at most once
+// per the interval given by WithKeyReloadInterval. The passphrases already passed are skipped.
+func (k2h *K2hash) ReloadKeys(ctx context.Context) (bool, error) {
+	if _, err := k2h.reloadKeys(ctx); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// reloadKeys passes the new passphrases of the KeyProvider to libk2hash, and returns the number of them.
+func (k2h *K2hash) reloadKeys(ctx context.Context) (int, error) {
+	if err := k2h.checkOpen("ReloadKeys", nil); err != nil {
+		return 0, err
+	}
+	if k2h.keyProvider == nil {
+		return 0, newOpError("ReloadKeys", nil, nil, fmt.Errorf("%w: no key provider", ErrInvalid))
+	}
+	k2h.keys.mu.Lock()
+	defer k2h.keys.mu.Unlock()
+	// 1. ask the provider
+	passes, err := k2h.keyProvider.Passphrases(ctx)
+	defer func() {
+		for _, pass := range passes {
+			clear(pass)
+		}
+	}()
+	if err != nil {
+		return 0, newOpError("ReloadKeys", nil, nil, err)
+	}
+	if len(passes) == 0 {
+		return 0, newOpError("ReloadKeys", nil, nil, fmt.Errorf("%w: no passphrase", ErrInvalid))
+	}
+	if k2h.keys.added == nil {
+		k2h.keys.added = make(map[[sha256.Size]byte]bool)
+	}
+	// 2. the new decryption passphrases, and then the default one if it is changed
+	n := 0
+	for _, pass := range passes[1:] {
+		d := k2h.keys.digest(pass)
+		if k2h.keys.added[d] {
+			continue
+		}
+		if err := k2h.addCryptPass("ReloadKeys", pass, false); err != nil {
+			return n, err
+		}
+		k2h.keys.added[d] = true
+		n++
+	}
+	if d := k2h.keys.digest(passes[0]); d != k2h.keys.current {
+		if err := k2h.addCryptPass("ReloadKeys", passes[0], true); err != nil {
+			return n, err
+		}
+		k2h.keys.added[d] = true
+		k2h.keys.current = d
+		n++
+	}
+	return n, nil
+}
+
+// retryDecrypt reloads the passphrases if the key exists with an encrypted value. It returns true if
+// the value should be read again, which means that new passphrases are passed to libk2hash.
+func (k2h *K2hash) retryDecrypt(key []byte, params *Params) bool {
+	if k2h.keyProvider == nil || params.password != "" || !k2h.exists(key) {
+		return false
+	}
+	if attrs, _ := k2h.GetAttrs(key); !attrs.IsEncrypted() {
+		return false
+	}
+	// 1. limit the rate of reloads
+	k2h.keys.mu.Lock()
+	now := time.Now()
+	if now.Before(k2h.keys.next) {
+		k2h.keys.mu.Unlock()
+		return false
+	}
+	k2h.keys.next = now.Add(k2h.keyReloadInterval)
+	k2h.keys.mu.Unlock()
+	// 2. reload
+	n, err := k2h.reloadKeys(context.Background())
+	return err == nil && n > 0
+}
+
+// addCryptPass passes a passphrase to libk2hash through a C buffer which is cleared after the call.
+func (k2h *K2hash) addCryptPass(op string, pass []byte, isDefault bool) error {
+	cPass := (*C.char)(C.malloc(C.size_t(len(pass) + 1)))
+	buf := unsafe.Slice((*byte)(unsafe.Pointer(cPass)), len(pass)+1)
+	defer func() {
+		clear(buf)
+		C.free(unsafe.Pointer(cPass))
+	}()
+	copy(buf, pass)
+	buf[len(pass)] = 0
+	if ok, errno := C.k2h_add_attr_crypt_pass(k2h.handle, cPass, C._Bool(isDefault)); !ok {
+		return newOpError(op, nil, errno, nil)
+	}
+	return nil
+}
+
+// Local Variables:
+// c-basic-offset: 4
+// tab-width: 4
+// indent-tabs-mode: t
+// End:
+// vim600: noexpandtab sw=4 ts=4 fdm=marker
+// vim<600: noexpandtab sw=4 ts=4
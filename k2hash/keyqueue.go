@@ -39,6 +39,8 @@ type KeyQueue struct {
 	fifo bool
 	// prefix
 	prefix string
+	// inflight counts operations abandoned by the context variants which are still running.
+	inflight *inflightOps
 }
 
 // String returns a text representation of the object.
@@ -48,12 +50,16 @@ func (q *KeyQueue) String() string {
 
 // NewKeyQueue returns a new k2hash queue instance.
 func NewKeyQueue(h *K2hash, options ...func(*KeyQueue)) (*KeyQueue, error) {
+	if err := h.checkOpen("NewKeyQueue", nil); err != nil {
+		return nil, err
+	}
 	// 1. set defaults
 	q := KeyQueue{
 		handle:     h.GetHandle(),
 		keyqhandle: C.K2H_INVALID_HANDLE,
 		fifo:       true,
 		prefix:     "",
+		inflight:   &h.inflight,
 	}
 	// 2. set options
 	for _, option := range options {
@@ -61,16 +67,17 @@ func NewKeyQueue(h *K2hash, options ...func(*KeyQueue)) (*KeyQueue, error) {
 	}
 	// 3. open
 	var qh C.k2h_keyq_h
+	var errno error
 	if q.prefix == "" {
-		qh = C.k2h_keyq_handle(q.handle, C._Bool(q.fifo))
+		qh, errno = C.k2h_keyq_handle(q.handle, C._Bool(q.fifo))
 	} else {
 		cPrefix := C.CBytes([]byte(q.prefix))
 		defer C.free(unsafe.Pointer(cPrefix))
-		qh = C.k2h_keyq_handle_prefix(q.handle, C._Bool(q.fifo), (*C.uchar)(cPrefix), C.size_t(len([]byte(q.prefix))))
+		qh, errno = C.k2h_keyq_handle_prefix(q.handle, C._Bool(q.fifo), (*C.uchar)(cPrefix), C.size_t(len([]byte(q.prefix))))
 	}
 	// 4. check qeueu handle
 	if qh == C.K2H_INVALID_HANDLE {
-		return nil, fmt.Errorf("C.k2h_keyq_handle return false")
+		return nil, newOpError("NewKeyQueue", nil, errno, nil)
 	}
 	// 5. reset keyqhandle
 	q.keyqhandle = qh
@@ -81,13 +88,13 @@ func NewKeyQueue(h *K2hash, options ...func(*KeyQueue)) (*KeyQueue, error) {
 
 // Push adds a value to the queue.
 func (q *KeyQueue) Push(v interface{}, options ...func(*Params)) (bool, error) {
+	if err := q.checkOpen("KeyQueue.Push"); err != nil {
+		return false, err
+	}
 	// 1. binary or text
-	var val string
-	switch v.(type) {
-	default:
-		return false, fmt.Errorf("unsupported key data format %T", v)
-	case string:
-		val = v.(string)
+	val, err := toBytes(v)
+	if err != nil {
+		return false, err
 	}
 	// 2. set params
 	params := Params{
@@ -97,6 +104,9 @@ func (q *KeyQueue) Push(v interface{}, options ...func(*Params)) (bool, error) {
 	for _, option := range options {
 		option(&params)
 	}
+	if err := params.validate(); err != nil {
+		return false, err
+	}
 	cPass := C.CString(params.password)
 	defer C.free(unsafe.Pointer(cPass))
 	var expire *C.time_t
@@ -104,31 +114,94 @@ func (q *KeyQueue) Push(v interface{}, options ...func(*Params)) (bool, error) {
 	if params.expirationDuration != 0 {
 		expire = (*C.time_t)(&params.expirationDuration)
 	}
-	cVal := C.CString(val)
+	cVal, cValLen := cBytes(val)
 	defer C.free(unsafe.Pointer(cVal))
-	if ok := C.k2h_keyq_str_push_wa(q.keyqhandle, (*C.char)(cVal), cPass, expire); !ok {
-		return false, fmt.Errorf("C.k2h_keyq_str_push return false")
+	if ok, errno := C.k2h_keyq_push_wa(q.keyqhandle, cVal, cValLen, cPass, expire); !ok {
+		return false, newOpError("KeyQueue.Push", nil, errno, nil)
 	}
 	return true, nil
 }
 
 // Pop retrieves a value from the queue.
 func (q *KeyQueue) Pop(options ...func(*Params)) (string, error) {
-	var cRetVal (*C.char)
+	val, err := q.PopBytes(options...)
+	if err != nil {
+		return "", err
+	}
+	return string(trimNull(val)), nil
+}
+
+// PopBytes retrieves a value from the queue in binary format.
+// A value pushed as a string contains the null termination.
+func (q *KeyQueue) PopBytes(options ...func(*Params)) ([]byte, error) {
+	if err := q.checkOpen("KeyQueue.Pop"); err != nil {
+		return nil, err
+	}
+	// 1. set params
+	params := Params{
+		password:           "",
+		expirationDuration: 0,
+	}
+	for _, option := range options {
+		option(&params)
+	}
+	// 2. pop
+	cPass := C.CString(params.password)
+	defer C.free(unsafe.Pointer(cPass))
+	var cRetVal *C.uchar
+	var cRetValLen C.size_t
+	ok, errno := C.k2h_keyq_pop_wp(q.keyqhandle, &cRetVal, &cRetValLen, cPass)
 	defer C.free(unsafe.Pointer(cRetVal))
-	ok := C.k2h_keyq_str_pop(q.keyqhandle, &cRetVal)
+	if !ok {
+		if C.k2h_keyq_empty(q.keyqhandle) {
+			return nil, newOpError("KeyQueue.Pop", nil, errno, ErrQueueEmpty)
+		}
+		return nil, newOpError("KeyQueue.Pop", nil, errno, nil)
+	}
+	return goBytes(cRetVal, cRetValLen), nil
+}
+
+// popKeyVal retrieves a key and the value of it from the queue in binary format.
+func (q *KeyQueue) popKeyVal(op string, options ...func(*Params)) ([2][]byte, error) {
+	var kv [2][]byte
+	if err := q.checkOpen(op); err != nil {
+		return kv, err
+	}
+	// 1. set params
+	params := Params{
+		password:           "",
+		expirationDuration: 0,
+	}
+	for _, option := range options {
+		option(&params)
+	}
+	// 2. pop
+	cPass := C.CString(params.password)
+	defer C.free(unsafe.Pointer(cPass))
+	var cRetKey, cRetVal *C.uchar
+	var cRetKeyLen, cRetValLen C.size_t
+	ok, errno := C.k2h_keyq_pop_keyval_wp(q.keyqhandle, &cRetKey, &cRetKeyLen, &cRetVal, &cRetValLen, cPass)
+	defer C.free(unsafe.Pointer(cRetKey))
 	defer C.free(unsafe.Pointer(cRetVal))
 	if !ok {
-		return "", fmt.Errorf("C.k2h_keyq_str_pop return false")
+		if C.k2h_keyq_empty(q.keyqhandle) {
+			return kv, newOpError(op, nil, errno, ErrQueueEmpty)
+		}
+		return kv, newOpError(op, nil, errno, nil)
 	}
-	val := C.GoString(cRetVal)
-	return val, nil
+	kv[0] = goBytes(cRetKey, cRetKeyLen)
+	kv[1] = goBytes(cRetVal, cRetValLen)
+	return kv, nil
 }
 
 // Free destroys a k2hash queue handle.
 func (q *KeyQueue) Free() (bool, error) {
-	if ok := C.k2h_keyq_free(q.keyqhandle); !ok {
-		return false, fmt.Errorf("k2h_keyq_free() returns false")
+	if err := q.checkOpen("KeyQueue.Free"); err != nil {
+		return false, err
+	}
+	q.inflight.Wait()
+	if ok, errno := C.k2h_keyq_free(q.keyqhandle); !ok {
+		return false, newOpError("KeyQueue.Free", nil, errno, nil)
 	}
 	q.keyqhandle = C.K2H_INVALID_HANDLE
 	return true, nil
@@ -136,10 +209,21 @@ func (q *KeyQueue) Free() (bool, error) {
 
 // Count returns the number of k2hash queue elements.
 func (q *KeyQueue) Count() (int, error) {
+	if err := q.checkOpen("KeyQueue.Count"); err != nil {
+		return 0, err
+	}
 	count := C.k2h_keyq_count(q.keyqhandle)
 	return int(count), nil
 }
 
+// checkOpen returns ErrClosed if the queue handle is already freed.
+func (q *KeyQueue) checkOpen(op string) error {
+	if q.keyqhandle == C.K2H_INVALID_HANDLE {
+		return newOpError(op, nil, nil, ErrClosed)
+	}
+	return nil
+}
+
 // Local Variables:
 // c-basic-offset: 4
 // tab-width: 4
@@ -0,0 +1,350 @@
+//
+// k2hash_go
+//
+// Copyright 2018 Yahoo Japan Corporation.
+//
+// Go driver for k2hash that is a NoSQL Key Value Store(KVS) library.
+// For k2hash, see https://github.com/yahoojapan/k2hash for the details.
+//
+// For the full copyright and license information, please view
+// the license file that was distributed with this source code.
+//
+// AUTHOR:   Hirotaka Wakabayashi
+// CREATE:   Sun, 18 Oct 2026
+// REVISION:
+//
+
+package k2hash
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// minMaskBitCount is the minimum number of key mask bits.
+	minMaskBitCount = 2
+	// maxMaskBitCount is the maximum number of key mask bits.
+	maxMaskBitCount = 63
+)
+
+/* -- K2hash options -- */
+
+// WithReadOnly opens a k2hash file in read only mode if true.
+func WithReadOnly(readonly bool) func(*K2hash) {
+	return func(k2h *K2hash) {
+		k2h.readonly = readonly
+	}
+}
+
+// WithRemoveFile removes a k2hash file automatically when no process attaches it if true.
+func WithRemoveFile(removefile bool) func(*K2hash) {
+	return func(k2h *K2hash) {
+		k2h.removefile = removefile
+	}
+}
+
+// WithFullMap maps a whole k2hash file into memory if true.
+func WithFullMap(fullmap bool) func(*K2hash) {
+	return func(k2h *K2hash) {
+		k2h.fullmap = fullmap
+	}
+}
+
+// WithMaskBits sets the number of key mask bits.
+func WithMaskBits(count int) func(*K2hash) {
+	return func(k2h *K2hash) {
+		k2h.maskbitcnt = count
+	}
+}
+
+// WithCollisionMaskBits sets the number of key collision mask bits.
+func WithCollisionMaskBits(count int) func(*K2hash) {
+	return func(k2h *K2hash) {
+		k2h.cmaskbitcnt = count
+	}
+}
+
+// WithMaxElements sets the max number of duplicated elements if a hash collision occurs.
+func WithMaxElements(count int) func(*K2hash) {
+	return func(k2h *K2hash) {
+		k2h.maxelementcnt = count
+	}
+}
+
+// WithPageSize sets the block size of data in bytes.
+func WithPageSize(size int) func(*K2hash) {
+	return func(k2h *K2hash) {
+		k2h.pagesize = size
+	}
+}
+
+// WithCloseWait sets the time to wait until a transaction is completed on Close.
+// A negative duration waits until all transactions are completed.
+func WithCloseWait(d time.Duration) func(*K2hash) {
+	return func(k2h *K2hash) {
+		if d < 0 {
+			k2h.waitms = -1
+		} else {
+			k2h.waitms = int(d / time.Millisecond)
+		}
+	}
+}
+
+// WithBufferPool makes Get use buffers of the pool for values. A GetResult must be released by Release to
+// return the buffer to the pool.
+func WithBufferPool(pool *BufferPool) func(*K2hash) {
+	return func(k2h *K2hash) {
+		k2h.pool = pool
+	}
+}
+
+// WithKeyProvider makes the handle ask the provider for the passphrases of the encryption attribute at open time
+// and when a value can't be decrypted. See ReloadKeys.
+func WithKeyProvider(provider KeyProvider) func(*K2hash) {
+	return func(k2h *K2hash) {
+		k2h.keyProvider = provider
+	}
+}
+
+// WithKeyReloadInterval sets the minimum interval of reloads of the passphrases on decrypt failures.
+// Zero means no limit.
+func WithKeyReloadInterval(interval time.Duration) func(*K2hash) {
+	return func(k2h *K2hash) {
+		k2h.keyReloadInterval = interval
+	}
+}
+
+// WithSweeper starts a background goroutine which removes expired keys every interval. It is stopped on Close.
+func WithSweeper(interval time.Duration) func(*K2hash) {
+	return func(k2h *K2hash) {
+		k2h.sweepInterval = interval
+	}
+}
+
+// WithSweepRate limits the number of keys the sweeper scans or removes per second. Zero means no limit.
+func WithSweepRate(rate int) func(*K2hash) {
+	return func(k2h *K2hash) {
+		k2h.sweepRate = rate
+	}
+}
+
+// validate checks the configurations before opening a k2hash file.
+func (k2h *K2hash) validate() error {
+	if k2h.maskbitcnt < minMaskBitCount || maxMaskBitCount < k2h.maskbitcnt {
+		return fmt.Errorf("%w: maskbitcnt %v is out of range [%v, %v]", ErrInvalid, k2h.maskbitcnt, minMaskBitCount, maxMaskBitCount)
+	}
+	if k2h.cmaskbitcnt <= 0 {
+		return fmt.Errorf("%w: cmaskbitcnt %v must be positive", ErrInvalid, k2h.cmaskbitcnt)
+	}
+	if k2h.maxelementcnt <= 0 {
+		return fmt.Errorf("%w: maxelementcnt %v must be positive", ErrInvalid, k2h.maxelementcnt)
+	}
+	if k2h.pagesize <= 0 {
+		return fmt.Errorf("%w: pagesize %v must be positive", ErrInvalid, k2h.pagesize)
+	}
+	if k2h.mode != modeFile && k2h.readonly {
+		return fmt.Errorf("%w: readonly can't be used with a %v database", ErrInvalid, k2h.mode)
+	}
+	if k2h.keyReloadInterval < 0 {
+		return fmt.Errorf("%w: key reload interval %v must not be negative", ErrInvalid, k2h.keyReloadInterval)
+	}
+	if k2h.sweepInterval < 0 {
+		return fmt.Errorf("%w: sweep interval %v must not be negative", ErrInvalid, k2h.sweepInterval)
+	}
+	if k2h.sweepRate < 0 {
+		return fmt.Errorf("%w: sweep rate %v must not be negative", ErrInvalid, k2h.sweepRate)
+	}
+	if k2h.sweepInterval > 0 && k2h.readonly {
+		return fmt.Errorf("%w: sweeper can't be used with readonly", ErrInvalid)
+	}
+	return nil
+}
+
+/* -- Params options -- */
+
+// WithPassword sets the passphrase to encrypt or decrypt a value.
+func WithPassword(pass string) func(*Params) {
+	return func(p *Params) {
+		p.password = pass
+	}
+}
+
+// WithExpire sets the expiration duration of a value. It is rounded up to seconds, and must be positive.
+func WithExpire(d time.Duration) func(*Params) {
+	return func(p *Params) {
+		if d <= 0 {
+			// rejected by validate, because zero means no expiration.
+			p.expirationDuration = -1
+			return
+		}
+		p.expirationDuration = int64((d + time.Second - 1) / time.Second)
+	}
+}
+
+// WithReplaceAttrs makes SetEntry replace the existing attributes with the attributes of the entry.
+// By default, the existing attributes which are not in the entry are kept.
+func WithReplaceAttrs() func(*Params) {
+	return func(p *Params) {
+		p.replaceAttrs = true
+	}
+}
+
+// validate checks the parameters before calling k2hash C API.
+func (p *Params) validate() error {
+	if p.expirationDuration < 0 {
+		return fmt.Errorf("%w: expirationDuration %v must not be negative", ErrInvalid, p.expirationDuration)
+	}
+	return nil
+}
+
+/* -- RotateParams options -- */
+
+// WithRotateCheckpoint saves the position of RotateEncryption to the file after every page of keys, so that
+// RotateEncryption resumes from the position after a crash. The file is removed when it is completed.
+func WithRotateCheckpoint(file string) func(*RotateParams) {
+	return func(p *RotateParams) {
+		p.checkpoint = file
+	}
+}
+
+// WithRotateProgress calls fn with the progress of RotateEncryption after every key.
+func WithRotateProgress(fn func(RotateProgress)) func(*RotateParams) {
+	return func(p *RotateParams) {
+		p.progress = fn
+	}
+}
+
+/* -- WalkParams options -- */
+
+// WithWalkDepth limits WalkSubKeys to keys at most depth levels below the root. The root is at depth zero,
+// and a negative depth means no limit.
+func WithWalkDepth(depth int) func(*WalkParams) {
+	return func(p *WalkParams) {
+		p.maxDepth = depth
+	}
+}
+
+// WithWalkMode sets the order of WalkSubKeys. default is WalkDFS.
+func WithWalkMode(mode WalkMode) func(*WalkParams) {
+	return func(p *WalkParams) {
+		p.mode = mode
+	}
+}
+
+/* -- TreeParams options -- */
+
+// WithTreeDryRun makes CopyTree and MoveTree report the changes without writing them.
+func WithTreeDryRun() func(*TreeParams) {
+	return func(p *TreeParams) {
+		p.dryRun = true
+	}
+}
+
+// WithTreeParent makes CopyTree and MoveTree add the destination key to the subkeys of the parent.
+func WithTreeParent(parent interface{}) func(*TreeParams) {
+	return func(p *TreeParams) {
+		p.parent = parent
+	}
+}
+
+// WithTreeOldParent makes MoveTree remove the source key from the subkeys of the old parent.
+func WithTreeOldParent(parent interface{}) func(*TreeParams) {
+	return func(p *TreeParams) {
+		p.oldParent = parent
+	}
+}
+
+// WithTreePrefixNaming makes CopyTree and MoveTree copy only the subkeys whose names begin with the name of
+// the source key followed by sep, and name the copies by replacing the beginning with the name of the destination.
+// The other subkeys are shared by the copies without being copied.
+func WithTreePrefixNaming(sep string) func(*TreeParams) {
+	return func(p *TreeParams) {
+		p.prefixNaming = true
+		p.separator = sep
+	}
+}
+
+/* -- RemoveParams options -- */
+
+// WithRemoveAll removes a key with all subkeys of it.
+func WithRemoveAll() func(*RemoveParams) {
+	return func(p *RemoveParams) {
+		p.all = true
+	}
+}
+
+// WithRemoveSubKey removes a subkey from a key. The subkey is either a string or a []byte.
+func WithRemoveSubKey(s interface{}) func(*RemoveParams) {
+	return func(p *RemoveParams) {
+		p.subkey = s
+	}
+}
+
+/* -- TxParams options -- */
+
+// WithTxPrefix sets the prefix of transaction keys.
+func WithTxPrefix(prefix string) func(*TxParams) {
+	return func(p *TxParams) {
+		p.prefix = prefix
+	}
+}
+
+// WithTxParams sets the parameter of transactions.
+func WithTxParams(params string) func(*TxParams) {
+	return func(p *TxParams) {
+		p.params = params
+	}
+}
+
+// WithTxExpire sets the expiration duration of transaction data. It is rounded up to seconds, and must be positive.
+func WithTxExpire(d time.Duration) func(*TxParams) {
+	return func(p *TxParams) {
+		if d <= 0 {
+			// rejected by BeginTx, because zero means no expiration.
+			p.expirationDuration = -1
+			return
+		}
+		p.expirationDuration = int64((d + time.Second - 1) / time.Second)
+	}
+}
+
+/* -- Queue options -- */
+
+// WithQueueFIFO sets whether a queue is first in first out. default is true.
+func WithQueueFIFO(fifo bool) func(*Queue) {
+	return func(q *Queue) {
+		q.fifo = fifo
+	}
+}
+
+// WithQueuePrefix sets the prefix of keys of queue elements.
+func WithQueuePrefix(prefix string) func(*Queue) {
+	return func(q *Queue) {
+		q.prefix = prefix
+	}
+}
+
+/* -- KeyQueue options -- */
+
+// WithKeyQueueFIFO sets whether a queue is first in first out. default is true.
+func WithKeyQueueFIFO(fifo bool) func(*KeyQueue) {
+	return func(q *KeyQueue) {
+		q.fifo = fifo
+	}
+}
+
+// WithKeyQueuePrefix sets the prefix of keys of queue elements.
+func WithKeyQueuePrefix(prefix string) func(*KeyQueue) {
+	return func(q *KeyQueue) {
+		q.prefix = prefix
+	}
+}
+
+// Local Variables:
+// c-basic-offset: 4
+// tab-width: 4
+// indent-tabs-mode: t
+// End:
+// vim600: noexpandtab sw=4 ts=4 fdm=marker
+// vim<600: noexpandtab sw=4 ts=4
@@ -0,0 +1,65 @@
+//
+// k2hash_go
+//
+// Copyright 2018 Yahoo Japan Corporation.
+//
+// Go driver for k2hash that is a NoSQL Key Value Store(KVS) library.
+// For k2hash, see https://github.com/yahoojapan/k2hash for the details.
+//
+// For the full copyright and license information, please view
+// the license file that was distributed with this source code.
+//
+// AUTHOR:   Hirotaka Wakabayashi
+// CREATE:   Sun, 18 Oct 2026
+// REVISION:
+//
+
+package k2hash
+
+import (
+	"sync"
+)
+
+// BufferPool is a pool of buffers for values, which reduces allocations on a hot read path.
+type BufferPool struct {
+	// pool keeps *[]byte to avoid allocations on Put. The pointer got from get is returned to put.
+	pool sync.Pool
+	// size is the initial capacity of a new buffer.
+	size int
+}
+
+// NewBufferPool returns a new pool of buffers whose initial capacity is size bytes.
+func NewBufferPool(size int) *BufferPool {
+	if size < 0 {
+		size = 0
+	}
+	p := BufferPool{
+		size: size,
+	}
+	return &p
+}
+
+// get returns a pointer to an empty buffer from the pool.
+func (p *BufferPool) get() *[]byte {
+	if b, ok := p.pool.Get().(*[]byte); ok {
+		*b = (*b)[:0]
+		return b
+	}
+	b := make([]byte, 0, p.size)
+	return &b
+}
+
+// put returns a buffer to the pool. b must be the pointer got from get, and it is updated with buf,
+// which may be reallocated by append.
+func (p *BufferPool) put(b *[]byte, buf []byte) {
+	*b = buf
+	p.pool.Put(b)
+}
+
+// Local Variables:
+// c-basic-offset: 4
+// tab-width: 4
+// indent-tabs-mode: t
+// End:
+// vim600: noexpandtab sw=4 ts=4 fdm=marker
+// vim<600: noexpandtab sw=4 ts=4
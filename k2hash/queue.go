@@ -39,6 +39,8 @@ type Queue struct {
 	fifo bool
 	// prefix
 	prefix string
+	// inflight counts operations abandoned by the context variants which are still running.
+	inflight *inflightOps
 }
 
 // String returns a text representation of the object.
@@ -48,12 +50,16 @@ func (q *Queue) String() string {
 
 // NewQueue returns a new k2hash queue instance.
 func NewQueue(h *K2hash, options ...func(*Queue)) (*Queue, error) {
+	if err := h.checkOpen("NewQueue", nil); err != nil {
+		return nil, err
+	}
 	// 1. set defaults
 	q := Queue{
-		handle:  h.GetHandle(),
-		qhandle: C.K2H_INVALID_HANDLE,
-		fifo:    true,
-		prefix:  "",
+		handle:   h.GetHandle(),
+		qhandle:  C.K2H_INVALID_HANDLE,
+		fifo:     true,
+		prefix:   "",
+		inflight: &h.inflight,
 	}
 	// 2. set options
 	for _, option := range options {
@@ -61,16 +67,17 @@ func NewQueue(h *K2hash, options ...func(*Queue)) (*Queue, error) {
 	}
 	// 3. open
 	var qh C.k2h_q_h
+	var errno error
 	if q.prefix == "" {
-		qh = C.k2h_q_handle(q.handle, C._Bool(q.fifo))
+		qh, errno = C.k2h_q_handle(q.handle, C._Bool(q.fifo))
 	} else {
 		cPrefix := C.CBytes([]byte(q.prefix))
 		defer C.free(unsafe.Pointer(cPrefix))
-		qh = C.k2h_q_handle_prefix(q.handle, C._Bool(q.fifo), (*C.uchar)(cPrefix), C.size_t(len([]byte(q.prefix))))
+		qh, errno = C.k2h_q_handle_prefix(q.handle, C._Bool(q.fifo), (*C.uchar)(cPrefix), C.size_t(len([]byte(q.prefix))))
 	}
 	// 4. check qeueu handle
 	if qh == C.K2H_INVALID_HANDLE {
-		return nil, fmt.Errorf("C.k2h_q_handle return false")
+		return nil, newOpError("NewQueue", nil, errno, nil)
 	}
 	// 5. reset qhandle
 	q.qhandle = qh
@@ -81,13 +88,13 @@ func NewQueue(h *K2hash, options ...func(*Queue)) (*Queue, error) {
 
 // Push adds a value to the queue.
 func (q *Queue) Push(v interface{}, options ...func(*Params)) (bool, error) {
+	if err := q.checkOpen("Queue.Push"); err != nil {
+		return false, err
+	}
 	// 1. binary or text
-	var val string
-	switch v.(type) {
-	default:
-		return false, fmt.Errorf("unsupported key data format %T", v)
-	case string:
-		val = v.(string)
+	val, err := toBytes(v)
+	if err != nil {
+		return false, err
 	}
 	// 2. set params
 	params := Params{
@@ -97,6 +104,9 @@ func (q *Queue) Push(v interface{}, options ...func(*Params)) (bool, error) {
 	for _, option := range options {
 		option(&params)
 	}
+	if err := params.validate(); err != nil {
+		return false, err
+	}
 	cPass := C.CString(params.password)
 	defer C.free(unsafe.Pointer(cPass))
 	var expire *C.time_t
@@ -104,31 +114,61 @@ func (q *Queue) Push(v interface{}, options ...func(*Params)) (bool, error) {
 	if params.expirationDuration != 0 {
 		expire = (*C.time_t)(&params.expirationDuration)
 	}
-	cVal := C.CString(val)
+	cVal, cValLen := cBytes(val)
 	defer C.free(unsafe.Pointer(cVal))
-	if ok := C.k2h_q_str_push_wa(q.qhandle, (*C.char)(cVal), nil, 0, cPass, expire); !ok {
-		return false, fmt.Errorf("C.k2h_q_str_push return false")
+	if ok, errno := C.k2h_q_push_wa(q.qhandle, cVal, cValLen, nil, 0, cPass, expire); !ok {
+		return false, newOpError("Queue.Push", nil, errno, nil)
 	}
 	return true, nil
 }
 
 // Pop retrieves a value from the queue.
 func (q *Queue) Pop(options ...func(*Params)) (string, error) {
-	var cRetVal (*C.char)
-	defer C.free(unsafe.Pointer(cRetVal))
-	ok := C.k2h_q_str_pop(q.qhandle, &cRetVal)
+	val, err := q.PopBytes(options...)
+	if err != nil {
+		return "", err
+	}
+	return string(trimNull(val)), nil
+}
+
+// PopBytes retrieves a value from the queue in binary format.
+// A value pushed as a string contains the null termination.
+func (q *Queue) PopBytes(options ...func(*Params)) ([]byte, error) {
+	if err := q.checkOpen("Queue.Pop"); err != nil {
+		return nil, err
+	}
+	// 1. set params
+	params := Params{
+		password:           "",
+		expirationDuration: 0,
+	}
+	for _, option := range options {
+		option(&params)
+	}
+	// 2. pop
+	cPass := C.CString(params.password)
+	defer C.free(unsafe.Pointer(cPass))
+	var cRetVal *C.uchar
+	var cRetValLen C.size_t
+	ok, errno := C.k2h_q_pop_wp(q.qhandle, &cRetVal, &cRetValLen, cPass)
 	defer C.free(unsafe.Pointer(cRetVal))
 	if !ok {
-		return "", fmt.Errorf("C.k2h_q_str_pop return false")
+		if C.k2h_q_empty(q.qhandle) {
+			return nil, newOpError("Queue.Pop", nil, errno, ErrQueueEmpty)
+		}
+		return nil, newOpError("Queue.Pop", nil, errno, nil)
 	}
-	val := C.GoString(cRetVal)
-	return val, nil
+	return goBytes(cRetVal, cRetValLen), nil
 }
 
 // Free destroys a k2hash queue handle.
 func (q *Queue) Free() (bool, error) {
-	if ok := C.k2h_q_free(q.qhandle); !ok {
-		return false, fmt.Errorf("k2h_q_free() returns false")
+	if err := q.checkOpen("Queue.Free"); err != nil {
+		return false, err
+	}
+	q.inflight.Wait()
+	if ok, errno := C.k2h_q_free(q.qhandle); !ok {
+		return false, newOpError("Queue.Free", nil, errno, nil)
 	}
 	q.qhandle = C.K2H_INVALID_HANDLE
 	return true, nil
@@ -136,10 +176,21 @@ func (q *Queue) Free() (bool, error) {
 
 // Count returns the number of k2hash queue elements.
 func (q *Queue) Count() (int, error) {
+	if err := q.checkOpen("Queue.Count"); err != nil {
+		return 0, err
+	}
 	count := C.k2h_q_count(q.qhandle)
 	return int(count), nil
 }
 
+// checkOpen returns ErrClosed if the queue handle is already freed.
+func (q *Queue) checkOpen(op string) error {
+	if q.qhandle == C.K2H_INVALID_HANDLE {
+		return newOpError(op, nil, nil, ErrClosed)
+	}
+	return nil
+}
+
 // Local Variables:
 // c-basic-offset: 4
 // tab-width: 4
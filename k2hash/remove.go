@@ -25,31 +25,30 @@ import (
 )
 
 import (
-	"fmt"
 	"unsafe"
 )
 
 // RemoveParams is a parameter set of remove.
 type RemoveParams struct {
 	all    bool
-	subkey string
+	subkey interface{}
 }
 
 // Remove removes a key or a subkey.
 func (k2h *K2hash) Remove(k interface{}, options ...func(*RemoveParams)) (bool, error) {
 	// 1. binary or text
-	var key string
-	switch k.(type) {
-	default:
-		return false, fmt.Errorf("unsupported key data format %T", k)
-	case string:
-		key = k.(string)
+	key, err := toBytes(k)
+	if err != nil {
+		return false, err
+	}
+	if err := k2h.checkWritable("Remove", key); err != nil {
+		return false, err
 	}
 
 	// 2. remove params
 	params := RemoveParams{
 		all:    false,
-		subkey: "",
+		subkey: nil,
 	}
 
 	for _, option := range options {
@@ -57,20 +56,25 @@ func (k2h *K2hash) Remove(k interface{}, options ...func(*RemoveParams)) (bool,
 	}
 
 	// 3. remove
-	cKey := C.CString(key)
+	cKey, cKeyLen := cBytes(key)
 	defer C.free(unsafe.Pointer(cKey))
 	ok := C._Bool(false)
+	var errno error
 	if params.all == true {
-		ok = C.k2h_remove_str_all(k2h.handle, (*C.char)(cKey))
-	} else if params.subkey != "" {
-		cSubKey := C.CString(params.subkey)
+		ok, errno = C.k2h_remove_all(k2h.handle, cKey, cKeyLen)
+	} else if params.subkey != nil {
+		subkey, err := toBytes(params.subkey)
+		if err != nil {
+			return false, err
+		}
+		cSubKey, cSubKeyLen := cBytes(subkey)
 		defer C.free(unsafe.Pointer(cSubKey))
-		ok = C.k2h_remove_str_subkey(k2h.handle, (*C.char)(cKey), (*C.char)(cSubKey))
+		ok, errno = C.k2h_remove_subkey(k2h.handle, cKey, cKeyLen, cSubKey, cSubKeyLen)
 	} else {
-		ok = C.k2h_remove_str(k2h.handle, (*C.char)(cKey))
+		ok, errno = C.k2h_remove(k2h.handle, cKey, cKeyLen)
 	}
 	if ok != true {
-		return false, fmt.Errorf("C.k2h_set_str_value_wa return false")
+		return false, k2h.keyError("Remove", key, errno)
 	}
 	return true, nil
 }
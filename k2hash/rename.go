@@ -25,36 +25,32 @@ import (
 )
 
 import (
-	"fmt"
 	"unsafe"
 )
 
 // Rename renames an old key with a new key.
 func (k2h *K2hash) Rename(o interface{}, n interface{}) (bool, error) {
 	// 1. binary or text
-	var old string
-	var new string
-	switch o.(type) {
-	default:
-		return false, fmt.Errorf("unsupported key data format %T", o)
-	case string:
-		old = o.(string)
+	old, err := toBytes(o)
+	if err != nil {
+		return false, err
 	}
-	switch n.(type) {
-	default:
-		return false, fmt.Errorf("unsupported key data format %T", n)
-	case string:
-		new = n.(string)
+	new, err := toBytes(n)
+	if err != nil {
+		return false, err
+	}
+	if err := k2h.checkWritable("Rename", old); err != nil {
+		return false, err
 	}
 
-	// 3. get from k2hash get API
-	cOld := C.CString(old)
+	// 2. rename by k2hash rename API
+	cOld, cOldLen := cBytes(old)
 	defer C.free(unsafe.Pointer(cOld))
-	cNew := C.CString(new)
+	cNew, cNewLen := cBytes(new)
 	defer C.free(unsafe.Pointer(cNew))
-	ok := C.k2h_rename_str(k2h.handle, (*C.char)(cOld), (*C.char)(cNew))
+	ok, errno := C.k2h_rename(k2h.handle, cOld, cOldLen, cNew, cNewLen)
 	if ok != true {
-		return false, fmt.Errorf("C.k2h_set_str_value_wa return false")
+		return false, k2h.keyError("Rename", old, errno)
 	}
 	return true, nil
 }
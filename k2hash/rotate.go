@@ -0,0 +1,227 @@
+//
+// k2hash_go
+//
+// Copyright 2018 Yahoo Japan Corporation.
+//
+// Go driver for k2hash that is a NoSQL Key Value Store(KVS) library.
+// For k2hash, see https://github.com/yahoojapan/k2hash for the details.
+//
+// For the full copyright and license information, please view
+// the license file that was distributed with this source code.
+//
+// AUTHOR:   Hirotaka Wakabayashi
+// CREATE:   Sun, 18 Oct 2026
+// REVISION:
+//
+
+package k2hash
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// rotatePageSize is the number of keys RotateEncryption handles between checkpoints.
+const rotatePageSize = 100
+
+// RotateParams is a parameter set of RotateEncryption.
+type RotateParams struct {
+	checkpoint string
+	progress   func(RotateProgress)
+}
+
+// RotateProgress holds the progress of RotateEncryption. The counts start from zero on resume.
+type RotateProgress struct {
+	// Scanned is the number of keys scanned.
+	Scanned int
+	// Rotated is the number of keys encrypted with the new passphrase.
+	Rotated int
+	// Skipped is the number of keys not encrypted, expired, or already encrypted with the new passphrase.
+	Skipped int
+	// Key is the last key handled.
+	Key []byte
+	// Token is the position where all keys before it are rotated, which is saved by WithRotateCheckpoint.
+	Token string
+}
+
+// String returns a text representation of the object.
+func (p RotateProgress) String() string {
+	return fmt.Sprintf("[%v, %v, %v, %v, %v]", p.Scanned, p.Rotated, p.Skipped, p.Key, p.Token)
+}
+
+// RotateEncryption re-encrypts all encrypted values with newPass, which becomes the default encryption
+// passphrase, while oldPass is added as a decryption passphrase so that keys not rotated yet stay readable.
+// Each key is rewritten by SetEntry with the value, the subkeys, the expiration and the other attributes.
+// Keys already encrypted with newPass are rewritten again or skipped, so that it is safe to run it again
+// after a crash. Use WithRotateCheckpoint to resume from the last position.
+func (k2h *K2hash) RotateEncryption(oldPass, newPass string, options ...func(*RotateParams)) (RotateProgress, error) {
+	return k2h.RotateEncryptionContext(context.Background(), oldPass, newPass, options...)
+}
+
+// RotateEncryptionContext is the same as RotateEncryption, but it stops when ctx is done.
+func (k2h *K2hash) RotateEncryptionContext(ctx context.Context, oldPass, newPass string, options ...func(*RotateParams)) (RotateProgress, error) {
+	progress := RotateProgress{}
+	if err := k2h.checkWritable("RotateEncryption", nil); err != nil {
+		return progress, err
+	}
+	if newPass == "" {
+		return progress, fmt.Errorf("%w: new passphrase is empty", ErrInvalid)
+	}
+
+	// 1. set params
+	params := RotateParams{
+		checkpoint: "",
+		progress:   nil,
+	}
+	for _, option := range options {
+		option(&params)
+	}
+
+	// 2. new values are encrypted with newPass, and oldPass is still available to decrypt
+	if _, err := k2h.AddDecryptionPassword(oldPass); err != nil {
+		return progress, err
+	}
+	if _, err := k2h.SetDefaultEncryptionPassword(newPass); err != nil {
+		return progress, err
+	}
+
+	// 3. resume from the checkpoint
+	c := k2h.NewScanCursor()
+	if params.checkpoint != "" {
+		token, err := os.ReadFile(params.checkpoint)
+		if err != nil && !os.IsNotExist(err) {
+			return progress, err
+		}
+		if len(token) > 0 {
+			if c, err = k2h.ResumeScanCursor(string(token)); err != nil {
+				return progress, err
+			}
+		}
+	}
+
+	// 4. rotate keys page by page. The find handle of the cursor is kept between pages, and stays on the last
+	// key of a page. The key is rotated with the next page after the handle leaves it, so that the rewrite
+	// doesn't disturb the scan. The checkpoint is the position before a page, where all keys are rotated.
+	defer c.Close()
+	var held []byte
+	for {
+		if err := ctx.Err(); err != nil {
+			return progress, err
+		}
+		token := c.Token()
+		var keys [][]byte
+		if held != nil {
+			keys = append(keys, held)
+			held = nil
+		}
+		if !c.Done() {
+			results, err := c.Next(rotatePageSize)
+			if err != nil {
+				return progress, err
+			}
+			for _, r := range results {
+				keys = append(keys, r.Key)
+			}
+		}
+		if len(keys) == 0 {
+			break
+		}
+		if !c.Done() {
+			held = keys[len(keys)-1]
+			keys = keys[:len(keys)-1]
+		}
+		for _, key := range keys {
+			rotated, err := k2h.rotateKey(key, oldPass, newPass)
+			if err != nil {
+				return progress, err
+			}
+			progress.Scanned++
+			if rotated {
+				progress.Rotated++
+			} else {
+				progress.Skipped++
+			}
+			progress.Key = key
+			if params.progress != nil {
+				params.progress(progress)
+			}
+		}
+		progress.Token = token
+		if params.checkpoint != "" {
+			if err := writeCheckpoint(params.checkpoint, progress.Token); err != nil {
+				return progress, err
+			}
+		}
+	}
+	progress.Token = c.Token()
+
+	// 5. completed
+	if params.checkpoint != "" {
+		if err := os.Remove(params.checkpoint); err != nil && !os.IsNotExist(err) {
+			return progress, err
+		}
+	}
+	return progress, nil
+}
+
+// rotateKey re-encrypts the value of a key with newPass. It returns false if the key is skipped.
+func (k2h *K2hash) rotateKey(key []byte, oldPass, newPass string) (bool, error) {
+	// 1. only encrypted and alive keys
+	attrs, err := k2h.GetAttrs(key)
+	if errors.Is(err, ErrNotFound) {
+		// removed by others
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	if !attrs.IsEncrypted() {
+		return false, nil
+	}
+	if expire := attrs.Expire(); !expire.IsZero() && !expire.After(time.Now()) {
+		return false, nil
+	}
+
+	// 2. decrypt with oldPass at first. A value decrypted by newPass is already rotated.
+	e, err := k2h.GetEntry(key, WithPassword(oldPass))
+	if errors.Is(err, ErrDecrypt) {
+		if _, nerr := k2h.GetEntry(key, WithPassword(newPass)); nerr == nil {
+			return false, nil
+		}
+		return false, err
+	} else if errors.Is(err, ErrNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	// 3. encrypt with newPass keeping the other parts
+	next := Entry{
+		Value:   e.Value,
+		SubKeys: e.SubKeys,
+		Expire:  e.Expire,
+	}
+	if _, err := k2h.SetEntry(key, next, WithPassword(newPass)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// writeCheckpoint saves a token to the file atomically.
+func writeCheckpoint(file string, token string) error {
+	tmp := file + ".tmp"
+	if err := os.WriteFile(tmp, []byte(token), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, file)
+}
+
+// Local Variables:
+// c-basic-offset: 4
+// tab-width: 4
+// indent-tabs-mode: t
+// End:
+// vim600: noexpandtab sw=4 ts=4 fdm=marker
+// vim<600: noexpandtab sw=4 ts=4
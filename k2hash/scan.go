@@ -0,0 +1,153 @@
+//
+// k2hash_go
+//
+// Copyright 2018 Yahoo Japan Corporation.
+//
+// Go driver for k2hash that is a NoSQL Key Value Store(KVS) library.
+// For k2hash, see https://github.com/yahoojapan/k2hash for the details.
+//
+// For the full copyright and license information, please view
+// the license file that was distributed with this source code.
+//
+// AUTHOR:   Hirotaka Wakabayashi
+// CREATE:   Sun, 18 Oct 2026
+// REVISION:
+//
+
+package k2hash
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"regexp"
+)
+
+// ScanParams is a parameter set of scan.
+type ScanParams struct {
+	values bool
+	attrs  bool
+}
+
+// WithScanValues makes a scan return the values of matched keys.
+func WithScanValues() func(*ScanParams) {
+	return func(p *ScanParams) {
+		p.values = true
+	}
+}
+
+// WithScanAttrs makes a scan return the attributes of matched keys.
+func WithScanAttrs() func(*ScanParams) {
+	return func(p *ScanParams) {
+		p.attrs = true
+	}
+}
+
+// ScanResult holds a key matched by a scan.
+type ScanResult struct {
+	// Key is the matched key. A key saved as a string contains the null termination.
+	Key []byte
+	// Value is the value of the key if WithScanValues is set.
+	Value []byte
+	// Attrs is the attributes of the key if WithScanAttrs is set.
+	Attrs Attrs
+}
+
+// String returns a text representation of the object.
+func (r *ScanResult) String() string {
+	return fmt.Sprintf("[%v, %v, %v]", r.Key, r.Value, r.Attrs)
+}
+
+// ScanPrefix returns keys which start with the prefix. The prefix is either a string or a []byte.
+// A string prefix doesn't contain the null termination, so that it matches keys saved as strings.
+func (k2h *K2hash) ScanPrefix(prefix interface{}, options ...func(*ScanParams)) ([]ScanResult, error) {
+	// 1. binary or text
+	var p []byte
+	switch v := prefix.(type) {
+	default:
+		return nil, fmt.Errorf("%w: unsupported data format %T", ErrInvalid, prefix)
+	case string:
+		p = []byte(v)
+	case []byte:
+		p = v
+	}
+	// 2. scan
+	return k2h.scan(func(key []byte) bool {
+		return bytes.HasPrefix(key, p)
+	}, options...)
+}
+
+// ScanMatch returns keys which match the pattern. The pattern is either a glob string in the syntax
+// of path.Match or a *regexp.Regexp. Keys saved as strings are matched without the null termination.
+func (k2h *K2hash) ScanMatch(pattern interface{}, options ...func(*ScanParams)) ([]ScanResult, error) {
+	// 1. glob or regexp
+	var match func(key []byte) bool
+	switch v := pattern.(type) {
+	default:
+		return nil, fmt.Errorf("%w: unsupported pattern format %T", ErrInvalid, pattern)
+	case string:
+		if _, err := path.Match(v, ""); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+		}
+		match = func(key []byte) bool {
+			ok, _ := path.Match(v, string(trimNull(key)))
+			return ok
+		}
+	case *regexp.Regexp:
+		match = func(key []byte) bool {
+			return v.Match(trimNull(key))
+		}
+	}
+	// 2. scan
+	return k2h.scan(match, options...)
+}
+
+// scan walks all keys and returns keys matched by match.
+func (k2h *K2hash) scan(match func(key []byte) bool, options ...func(*ScanParams)) ([]ScanResult, error) {
+	// 1. set params
+	params := ScanParams{
+		values: false,
+		attrs:  false,
+	}
+	for _, option := range options {
+		option(&params)
+	}
+	// 2. walk keys
+	c, err := k2h.NewCursor()
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+	results := []ScanResult{}
+	for c.Next() {
+		if !match(c.Key()) {
+			continue
+		}
+		r := ScanResult{
+			Key: c.Key(),
+		}
+		if params.values {
+			if r.Value, err = c.Value(); err != nil {
+				return nil, err
+			}
+		}
+		if params.attrs {
+			if r.Attrs, err = k2h.GetAttrs(r.Key); err != nil {
+				return nil, err
+			}
+		}
+		results = append(results, r)
+	}
+	if err := c.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// Local Variables:
+// c-basic-offset: 4
+// tab-width: 4
+// indent-tabs-mode: t
+// End:
+// vim600: noexpandtab sw=4 ts=4 fdm=marker
+// vim<600: noexpandtab sw=4 ts=4
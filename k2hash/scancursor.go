@@ -0,0 +1,355 @@
+//
+// k2hash_go
+//
+// Copyright 2018 Yahoo Japan Corporation.
+//
+// Go driver for k2hash that is a NoSQL Key Value Store(KVS) library.
+// For k2hash, see https://github.com/yahoojapan/k2hash for the details.
+//
+// For the full copyright and license information, please view
+// the license file that was distributed with this source code.
+//
+// AUTHOR:   Hirotaka Wakabayashi
+// CREATE:   Sun, 18 Oct 2026
+// REVISION:
+//
+
+package k2hash
+
+import (
+	// #cgo CFLAGS: -g -O2 -Wall -Wextra -Wno-unused-variable -Wno-unused-parameter -I. -I/usr/include/k2hash
+	// #cgo LDFLAGS: -L/usr/lib -lk2hash
+	// #include <stdlib.h>
+	// #include "k2hash.h"
+	//
+	// // k2h_go_find_skip returns a find handle which is moved forward by count keys from the first key.
+	// static k2h_find_h k2h_go_find_skip(k2h_h handle, unsigned long count) {
+	//      k2h_find_h fhandle = k2h_find_first(handle);
+	//      for (; K2H_INVALID_HANDLE != fhandle && 0 < count; --count) {
+	//          fhandle = k2h_find_next(fhandle);
+	//      }
+	//      return fhandle;
+	// }
+	//
+	// // k2h_go_find_hash returns true if the key of the find handle has the 64-bit FNV-1a hash.
+	// static bool k2h_go_find_hash(k2h_find_h fhandle, uint64_t hash) {
+	//      unsigned char* pkey = NULL;
+	//      size_t keylength = 0;
+	//      uint64_t h = 14695981039346656037ULL;
+	//      size_t i;
+	//      if (!k2h_find_get_key(fhandle, &pkey, &keylength)) {
+	//          return false;
+	//      }
+	//      for (i = 0; i < keylength; ++i) {
+	//          h = (h ^ pkey[i]) * 1099511628211ULL;
+	//      }
+	//      free(pkey);
+	//      return h == hash;
+	// }
+	//
+	// // k2h_go_find_resume returns a find handle at the key with the hash, which is the count-th key unless
+	// // keys are added or removed before it, and sets its position to *ppos. If no key has the hash, it
+	// // returns the (count - 1)-th key with *pfound false, or K2H_INVALID_HANDLE if fewer keys exist.
+	// static k2h_find_h k2h_go_find_resume(k2h_h handle, unsigned long count, uint64_t hash, bool* pfound,
+	//                                      unsigned long* ppos) {
+	//      k2h_find_h fhandle = k2h_go_find_skip(handle, count - 1);
+	//      unsigned long pos;
+	//      *pfound = false;
+	//      if (K2H_INVALID_HANDLE != fhandle) {
+	//          if (k2h_go_find_hash(fhandle, hash)) {
+	//              *pfound = true;
+	//              *ppos = count;
+	//              return fhandle;
+	//          }
+	//          k2h_find_free(fhandle);
+	//      }
+	//      for (pos = 1, fhandle = k2h_find_first(handle); K2H_INVALID_HANDLE != fhandle; ++pos) {
+	//          if (k2h_go_find_hash(fhandle, hash)) {
+	//              *pfound = true;
+	//              *ppos = pos;
+	//              return fhandle;
+	//          }
+	//          fhandle = k2h_find_next(fhandle);
+	//      }
+	//      if (count < 2) {
+	//          return K2H_INVALID_HANDLE;
+	//      }
+	//      return k2h_go_find_skip(handle, count - 2);
+	// }
+	"C"
+)
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"sync"
+)
+
+// scanTokenVersion is the format version of scan tokens.
+const scanTokenVersion = 3
+
+// scanTokenDone is the flag of a token of a completed scan.
+const scanTokenDone = 1
+
+// maxParkedScans is the max number of find handles kept for scans between pages.
+const maxParkedScans = 64
+
+// ScanCursor returns keys in a k2hash file page by page. The position is saved as an opaque token of
+// a bounded size, so that a scan can be resumed by ResumeScanCursor with another handle or in another
+// process. The token contains a hash of the last key returned, but not the key itself.
+//
+// The k2hash find handle of a scan is kept by the K2hash between pages, so that the next page continues
+// from it even if it is read by a cursor resumed from the token. At most 64 handles are kept, and the least
+// recently used one is freed. If the handle is not kept, for example in another process, the next page
+// finds the last key from the first key once, and then keeps the handle again.
+type ScanCursor struct {
+	// k2h is the k2hash file.
+	k2h *K2hash
+	// params is the parameter set of scan.
+	params ScanParams
+	// id identifies the find handle kept for the scan.
+	id uint64
+	// count is the number of keys walked by the last page, including the staging keys.
+	count uint64
+	// last is the hash of the last key returned.
+	last uint64
+	// done is true if no more keys exist.
+	done bool
+}
+
+// String returns a text representation of the object.
+func (c *ScanCursor) String() string {
+	return fmt.Sprintf("[%v, %v, %v, %v]", c.id, c.count, c.last, c.done)
+}
+
+// NewScanCursor returns a new scan cursor which starts from the first key.
+func (k2h *K2hash) NewScanCursor(options ...func(*ScanParams)) *ScanCursor {
+	params := ScanParams{
+		values: false,
+		attrs:  false,
+	}
+	for _, option := range options {
+		option(&params)
+	}
+	c := ScanCursor{
+		k2h:    k2h,
+		params: params,
+		id:     rand.Uint64(),
+	}
+	return &c
+}
+
+// ResumeScanCursor returns a scan cursor which starts from the position saved in the token.
+// An empty token starts from the first key, and the token of a completed scan returns a cursor which is done.
+func (k2h *K2hash) ResumeScanCursor(token string, options ...func(*ScanParams)) (*ScanCursor, error) {
+	c := k2h.NewScanCursor(options...)
+	if token == "" {
+		return c, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(b) < 18 || b[0] != scanTokenVersion {
+		return nil, fmt.Errorf("%w: malformed scan token", ErrInvalid)
+	}
+	count, n := binary.Uvarint(b[10:])
+	if n <= 0 || len(b) != 18+n {
+		return nil, fmt.Errorf("%w: malformed scan token", ErrInvalid)
+	}
+	c.done = b[1]&scanTokenDone != 0
+	c.id = binary.BigEndian.Uint64(b[2:10])
+	c.count = count
+	c.last = binary.BigEndian.Uint64(b[10+n:])
+	return c, nil
+}
+
+// Token returns the opaque token of the current position. The token of a completed scan is never empty,
+// so that it is not mistaken for a new scan.
+func (c *ScanCursor) Token() string {
+	var flags byte
+	if c.done {
+		flags |= scanTokenDone
+	}
+	b := []byte{scanTokenVersion, flags}
+	b = binary.BigEndian.AppendUint64(b, c.id)
+	b = binary.AppendUvarint(b, c.count)
+	b = binary.BigEndian.AppendUint64(b, c.last)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// Done returns true if no more keys exist.
+func (c *ScanCursor) Done() bool {
+	return c.done
+}
+
+// Close frees the find handle kept for the next page. The token is still valid after Close.
+func (c *ScanCursor) Close() {
+	if cur := c.k2h.scans.take(c.id, c.count); cur != nil {
+		cur.Close()
+	}
+}
+
+// Next returns the next page of at most n keys.
+// If the last key of the previous page is removed, it resumes from the number of keys already returned,
+// so that some keys may be skipped or returned again if other keys are also added or removed.
+func (c *ScanCursor) Next(n int) ([]ScanResult, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("%w: page size %v must be positive", ErrInvalid, n)
+	}
+	results := []ScanResult{}
+	if c.done {
+		return results, nil
+	}
+	// 1. take the find handle of the previous page, or move to the position
+	cur := c.k2h.scans.take(c.id, c.count)
+	if cur == nil {
+		var err error
+		if cur, err = c.seek(); err != nil {
+			return nil, err
+		}
+	}
+	// 2. read a page
+	for len(results) < n {
+		if !cur.Next() {
+			if err := cur.Err(); err != nil {
+				cur.Close()
+				return nil, err
+			}
+			c.done = true
+			break
+		}
+		r := ScanResult{
+			Key: cur.Key(),
+		}
+		var err error
+		if c.params.values {
+			r.Value, err = cur.Value()
+		}
+		if err == nil && c.params.attrs {
+			r.Attrs, err = c.k2h.GetAttrs(r.Key)
+		}
+		if err != nil {
+			cur.Close()
+			return nil, err
+		}
+		results = append(results, r)
+		c.count = cur.pos
+		c.last = scanKeyHash(r.Key)
+	}
+	// 3. keep the find handle for the next page
+	if c.done {
+		cur.Close()
+	} else {
+		c.k2h.scans.park(c.id, c.count, cur)
+	}
+	return results, nil
+}
+
+// seek returns a cursor positioned at the last key returned.
+func (c *ScanCursor) seek() (*Cursor, error) {
+	cur, err := c.k2h.NewCursor()
+	if err != nil || c.count == 0 {
+		return cur, err
+	}
+	// 1. find the last key in C, which is the count-th key unless keys are added or removed.
+	var found C.bool
+	var pos C.ulong
+	fhandle := C.k2h_go_find_resume(c.k2h.handle, C.ulong(c.count), C.uint64_t(c.last), &found, &pos)
+	cur.started = true
+	cur.fhandle = fhandle
+	if found == true {
+		c.count = uint64(pos)
+		cur.pos = c.count
+		return cur, nil
+	}
+	// 2. resume from the number of keys if the last key is removed. The keys after it are moved forward
+	// by one unless other keys are also added or removed. k2h_find_next of an invalid handle ends the walk.
+	c.count--
+	cur.pos = c.count
+	if c.count == 0 {
+		cur.started = false
+	}
+	return cur, nil
+}
+
+// scanKeyHash returns the hash of a key saved in a scan token.
+func scanKeyHash(key []byte) uint64 {
+	h := fnv.New64a()
+	h.Write(key)
+	return h.Sum64()
+}
+
+// parkedScan is a find handle kept for the next page of a scan.
+type parkedScan struct {
+	// cur is the cursor positioned at the last key returned.
+	cur *Cursor
+	// count is the number of keys walked by the last page, including the staging keys.
+	count uint64
+	// used is the sequence number of the last use.
+	used uint64
+}
+
+// scanParking keeps the find handles of scans between pages.
+type scanParking struct {
+	// mu protects the following fields.
+	mu sync.Mutex
+	// scans is the find handles by the id of the scan.
+	scans map[uint64]*parkedScan
+	// seq is the sequence number of uses.
+	seq uint64
+}
+
+// park keeps a cursor for the next page, and frees the least recently used one if too many are kept.
+func (p *scanParking) park(id uint64, count uint64, cur *Cursor) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.scans == nil {
+		p.scans = make(map[uint64]*parkedScan)
+	}
+	if old, ok := p.scans[id]; ok {
+		old.cur.Close()
+	} else if len(p.scans) >= maxParkedScans {
+		var lru *parkedScan
+		var lruID uint64
+		for k, s := range p.scans {
+			if lru == nil || s.used < lru.used {
+				lru, lruID = s, k
+			}
+		}
+		lru.cur.Close()
+		delete(p.scans, lruID)
+	}
+	p.seq++
+	p.scans[id] = &parkedScan{cur: cur, count: count, used: p.seq}
+}
+
+// take returns the cursor kept for the scan, or nil if it is not kept at the position.
+func (p *scanParking) take(id uint64, count uint64) *Cursor {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s, ok := p.scans[id]
+	if !ok || s.count != count {
+		return nil
+	}
+	delete(p.scans, id)
+	return s.cur
+}
+
+// closeAll frees all cursors.
+func (p *scanParking) closeAll() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for id, s := range p.scans {
+		s.cur.Close()
+		delete(p.scans, id)
+	}
+}
+
+// Local Variables:
+// c-basic-offset: 4
+// tab-width: 4
+// indent-tabs-mode: t
+// End:
+// vim600: noexpandtab sw=4 ts=4 fdm=marker
+// vim<600: noexpandtab sw=4 ts=4
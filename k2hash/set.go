@@ -25,26 +25,24 @@ import (
 )
 
 import (
-	"fmt"
 	"unsafe"
 )
 
 // Set returns true if successfully set a key with a value.
+// A key and a value are either a string or a []byte. A string is saved with a null termination,
+// which is compatible with the k2hash C string API. A []byte is saved as it is.
 func (k2h *K2hash) Set(k interface{}, v interface{}, options ...func(*Params)) (bool, error) {
 	// 1. binary or text
-	var key string
-	var val string
-	switch k.(type) {
-	default:
-		return false, fmt.Errorf("unsupported key data format %T", k)
-	case string:
-		key = k.(string)
+	key, err := toBytes(k)
+	if err != nil {
+		return false, err
 	}
-	switch v.(type) {
-	default:
-		return false, fmt.Errorf("unsupported key data format %T", v)
-	case string:
-		val = v.(string)
+	val, err := toBytes(v)
+	if err != nil {
+		return false, err
+	}
+	if err := k2h.checkWritable("Set", key); err != nil {
+		return false, err
 	}
 
 	// 2. set params
@@ -56,11 +54,14 @@ func (k2h *K2hash) Set(k interface{}, v interface{}, options ...func(*Params)) (
 	for _, option := range options {
 		option(&params)
 	}
+	if err := params.validate(); err != nil {
+		return false, err
+	}
 
-	// 3. get from k2hash get API
-	cKey := C.CString(key)
+	// 3. set to k2hash set API
+	cKey, cKeyLen := cBytes(key)
 	defer C.free(unsafe.Pointer(cKey))
-	cVal := C.CString(val)
+	cVal, cValLen := cBytes(val)
 	defer C.free(unsafe.Pointer(cVal))
 	cPass := C.CString(params.password)
 	defer C.free(unsafe.Pointer(cPass))
@@ -69,9 +70,9 @@ func (k2h *K2hash) Set(k interface{}, v interface{}, options ...func(*Params)) (
 	if params.expirationDuration != 0 {
 		expire = (*C.time_t)(&params.expirationDuration)
 	}
-	ok := C.k2h_set_str_value_wa(k2h.handle, (*C.char)(cKey), (*C.char)(cVal), cPass, expire)
+	ok, errno := C.k2h_set_value_wa(k2h.handle, cKey, cKeyLen, cVal, cValLen, cPass, expire)
 	if ok != true {
-		return false, fmt.Errorf("C.k2h_set_str_value_wa return false")
+		return false, newOpError("Set", key, errno, nil)
 	}
 	return true, nil
 }
@@ -25,41 +25,34 @@ import (
 )
 
 import (
-	"fmt"
 	"unsafe"
 )
 
 // SetSubKeys links another key as a child to the key.
+// Subkeys are either a []string or a [][]byte.
 func (k2h *K2hash) SetSubKeys(k interface{}, sk interface{}) (bool, error) {
 	// 1. binary or text
-	var key string
-	var skeys []string
-	switch k.(type) {
-	default:
-		return false, fmt.Errorf("unsupported key data format %T", k)
-	case string:
-		key = k.(string)
+	key, err := toBytes(k)
+	if err != nil {
+		return false, err
 	}
-	switch sk.(type) {
-	default:
-		return false, fmt.Errorf("unsupported key data format %T", sk)
-	case []string:
-		skeys = sk.([]string)
+	skeys, err := toBytesSlice(sk)
+	if err != nil {
+		return false, err
+	}
+	if err := k2h.checkWritable("SetSubKeys", key); err != nil {
+		return false, err
 	}
 
 	// 2. set subkeys
-	cKey := C.CString(key)
+	cKey, cKeyLen := cBytes(key)
 	defer C.free(unsafe.Pointer(cKey))
-	cSkeys := make([]*C.char, len(skeys)+1, len(skeys)+1)
-	for i, s := range skeys {
-		cSkeys[i] = C.CString(s)
-		defer C.free(unsafe.Pointer(cSkeys[i]))
-	}
-	cSkeys[len(skeys)] = nil
+	cSkeys := newKeyPack(skeys)
+	defer freeKeyPack(cSkeys, len(skeys))
 
-	ok := C.k2h_set_str_subkeys(k2h.handle, (*C.char)(cKey), (**C.char)(&cSkeys[0]))
+	ok, errno := C.k2h_set_subkeys(k2h.handle, cKey, cKeyLen, cSkeys, C.int(len(skeys)))
 	if ok != true {
-		return false, fmt.Errorf("C.k2h_set_str_subkeys return false")
+		return false, newOpError("SetSubKeys", key, errno, nil)
 	}
 	return true, nil
 }
@@ -0,0 +1,96 @@
+//
+// k2hash_go
+//
+// Copyright 2018 Yahoo Japan Corporation.
+//
+// Go driver for k2hash that is a NoSQL Key Value Store(KVS) library.
+// For k2hash, see https://github.com/yahoojapan/k2hash for the details.
+//
+// For the full copyright and license information, please view
+// the license file that was distributed with this source code.
+//
+// AUTHOR:   Hirotaka Wakabayashi
+// CREATE:   Sun, 18 Oct 2026
+// REVISION:
+//
+
+package k2hash
+
+import (
+	"context"
+	"reflect"
+)
+
+// Store saves typed values to a k2hash database by using a Codec.
+// A key of a string type is saved with the null termination like Set.
+type Store[K ~string | ~[]byte, V any] struct {
+	// k2h is the k2hash database.
+	k2h *K2hash
+	// codec encodes and decodes values.
+	codec Codec
+}
+
+// NewStore returns a new Store of the k2hash database. JSONCodec is used if codec is nil.
+func NewStore[K ~string | ~[]byte, V any](k2h *K2hash, codec Codec) *Store[K, V] {
+	if codec == nil {
+		codec = JSONCodec{}
+	}
+	s := Store[K, V]{
+		k2h:   k2h,
+		codec: codec,
+	}
+	return &s
+}
+
+// key returns the key of id in the same format as Set.
+func (s *Store[K, V]) key(id K) []byte {
+	if reflect.TypeOf(id).Kind() == reflect.String {
+		key, _ := toBytes(string(id))
+		return key
+	}
+	return []byte(id)
+}
+
+// Put encodes v and saves it. It returns a CodecError if v can't be encoded.
+func (s *Store[K, V]) Put(ctx context.Context, id K, v V, options ...func(*Params)) error {
+	key := s.key(id)
+	val, err := s.codec.Marshal(v)
+	if err != nil {
+		return &CodecError{Op: "Store.Put", Key: key, Err: err}
+	}
+	if _, err := s.k2h.SetContext(ctx, key, val, options...); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Get returns the decoded value of id. It returns a CodecError if the value can't be decoded.
+func (s *Store[K, V]) Get(ctx context.Context, id K, options ...func(*Params)) (V, error) {
+	var v V
+	key := s.key(id)
+	r, err := s.k2h.GetContext(ctx, key, options...)
+	if err != nil {
+		return v, err
+	}
+	defer r.Release()
+	if err := s.codec.Unmarshal(r.Bytes(), &v); err != nil {
+		return v, &CodecError{Op: "Store.Get", Key: key, Err: err}
+	}
+	return v, nil
+}
+
+// Delete removes the value of id.
+func (s *Store[K, V]) Delete(ctx context.Context, id K) error {
+	if _, err := s.k2h.RemoveContext(ctx, s.key(id)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Local Variables:
+// c-basic-offset: 4
+// tab-width: 4
+// indent-tabs-mode: t
+// End:
+// vim600: noexpandtab sw=4 ts=4 fdm=marker
+// vim<600: noexpandtab sw=4 ts=4
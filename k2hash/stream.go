@@ -0,0 +1,277 @@
+//
+// k2hash_go
+//
+// Copyright 2018 Yahoo Japan Corporation.
+//
+// Go driver for k2hash that is a NoSQL Key Value Store(KVS) library.
+// For k2hash, see https://github.com/yahoojapan/k2hash for the details.
+//
+// For the full copyright and license information, please view
+// the license file that was distributed with this source code.
+//
+// AUTHOR:   Hirotaka Wakabayashi
+// CREATE:   Sun, 18 Oct 2026
+// REVISION:
+//
+
+package k2hash
+
+import (
+	// #cgo CFLAGS: -g -O2 -Wall -Wextra -Wno-unused-variable -Wno-unused-parameter -I. -I/usr/include/k2hash
+	// #cgo LDFLAGS: -L/usr/lib -lk2hash
+	// #include <stdlib.h>
+	// #include "k2hash.h"
+	"C"
+)
+
+import (
+	"fmt"
+	"io"
+	"unsafe"
+)
+
+// valueReader reads a value by using a k2hash direct access handle.
+type valueReader struct {
+	// key is the key of the value.
+	key []byte
+	// dahandle is a k2hash direct access handle.
+	dahandle C.k2h_da_h
+	// offset is the offset to read next.
+	offset int64
+}
+
+// valueWriter writes a value by using a k2hash direct access handle.
+type valueWriter struct {
+	// key is the key of the value.
+	key []byte
+	// dahandle is a k2hash direct access handle.
+	dahandle C.k2h_da_h
+	// offset is the offset to write next.
+	offset int64
+}
+
+// OpenReader returns a reader of the value of the key, which reads the value partially without loading the
+// whole value. The returned reader also implements io.ReaderAt. It must be closed by Close.
+// The direct access API reads raw data, so that attributes like encryption and expiration are not applied.
+func (k2h *K2hash) OpenReader(k interface{}) (io.ReadSeekCloser, error) {
+	// 1. binary or text
+	key, err := toBytes(k)
+	if err != nil {
+		return nil, err
+	}
+	if err := k2h.checkOpen("OpenReader", key); err != nil {
+		return nil, err
+	}
+	// 2. open a direct access handle
+	cKey, cKeyLen := cBytes(key)
+	defer C.free(unsafe.Pointer(cKey))
+	dahandle, errno := C.k2h_da_handle_read(k2h.handle, cKey, cKeyLen)
+	if dahandle == C.K2H_INVALID_HANDLE {
+		return nil, k2h.keyError("OpenReader", key, errno)
+	}
+	r := valueReader{
+		key:      key,
+		dahandle: dahandle,
+		offset:   0,
+	}
+	return &r, nil
+}
+
+// OpenWriter returns a writer of the value of the key, which writes the value partially from the offset.
+// The data after the written range is kept, so remove the key before writing to replace the whole value.
+// The returned writer also implements io.Seeker and io.WriterAt. It must be closed by Close.
+// The direct access API writes raw data, so that attributes like encryption and expiration are not applied.
+func (k2h *K2hash) OpenWriter(k interface{}) (io.WriteCloser, error) {
+	// 1. binary or text
+	key, err := toBytes(k)
+	if err != nil {
+		return nil, err
+	}
+	if err := k2h.checkWritable("OpenWriter", key); err != nil {
+		return nil, err
+	}
+	// 2. open a direct access handle
+	cKey, cKeyLen := cBytes(key)
+	defer C.free(unsafe.Pointer(cKey))
+	dahandle, errno := C.k2h_da_handle_write(k2h.handle, cKey, cKeyLen)
+	if dahandle == C.K2H_INVALID_HANDLE {
+		return nil, newOpError("OpenWriter", key, errno, nil)
+	}
+	w := valueWriter{
+		key:      key,
+		dahandle: dahandle,
+		offset:   0,
+	}
+	return &w, nil
+}
+
+/* -- valueReader methods -- */
+
+// String returns a text representation of the object.
+func (r *valueReader) String() string {
+	return fmt.Sprintf("[%v, %v, %v]", r.key, r.dahandle, r.offset)
+}
+
+// Read reads the value from the current offset.
+func (r *valueReader) Read(p []byte) (int, error) {
+	n, err := r.ReadAt(p, r.offset)
+	r.offset += int64(n)
+	return n, err
+}
+
+// ReadAt reads the value from the offset.
+func (r *valueReader) ReadAt(p []byte, off int64) (int, error) {
+	if r.dahandle == C.K2H_INVALID_HANDLE {
+		return 0, newOpError("Reader.Read", r.key, nil, ErrClosed)
+	}
+	if off < 0 {
+		return 0, fmt.Errorf("%w: negative offset %v", ErrInvalid, off)
+	}
+	length, err := daLength(r.dahandle, "Reader.Read", r.key)
+	if err != nil {
+		return 0, err
+	}
+	if off >= length {
+		return 0, io.EOF
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	var cVal *C.uchar
+	cValLen := C.size_t(len(p))
+	ok, errno := C.k2h_da_get_value_offset(r.dahandle, &cVal, &cValLen, C.off_t(off))
+	defer C.free(unsafe.Pointer(cVal))
+	if !ok {
+		return 0, newOpError("Reader.Read", r.key, errno, nil)
+	}
+	n := copy(p, goBytes(cVal, cValLen))
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Seek sets the offset to read next.
+func (r *valueReader) Seek(offset int64, whence int) (int64, error) {
+	if r.dahandle == C.K2H_INVALID_HANDLE {
+		return 0, newOpError("Reader.Seek", r.key, nil, ErrClosed)
+	}
+	abs, err := daSeek(r.dahandle, "Reader.Seek", r.key, r.offset, offset, whence)
+	if err != nil {
+		return 0, err
+	}
+	r.offset = abs
+	return abs, nil
+}
+
+// Close frees the k2hash direct access handle.
+func (r *valueReader) Close() error {
+	return daFree(&r.dahandle, "Reader.Close", r.key)
+}
+
+/* -- valueWriter methods -- */
+
+// String returns a text representation of the object.
+func (w *valueWriter) String() string {
+	return fmt.Sprintf("[%v, %v, %v]", w.key, w.dahandle, w.offset)
+}
+
+// Write writes data to the value from the current offset.
+func (w *valueWriter) Write(p []byte) (int, error) {
+	n, err := w.WriteAt(p, w.offset)
+	w.offset += int64(n)
+	return n, err
+}
+
+// WriteAt writes data to the value from the offset.
+func (w *valueWriter) WriteAt(p []byte, off int64) (int, error) {
+	if w.dahandle == C.K2H_INVALID_HANDLE {
+		return 0, newOpError("Writer.Write", w.key, nil, ErrClosed)
+	}
+	if off < 0 {
+		return 0, fmt.Errorf("%w: negative offset %v", ErrInvalid, off)
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	cVal, cValLen := cBytes(p)
+	defer C.free(unsafe.Pointer(cVal))
+	ok, errno := C.k2h_da_set_value_offset(w.dahandle, cVal, cValLen, C.off_t(off))
+	if !ok {
+		return 0, newOpError("Writer.Write", w.key, errno, nil)
+	}
+	return len(p), nil
+}
+
+// Seek sets the offset to write next.
+func (w *valueWriter) Seek(offset int64, whence int) (int64, error) {
+	if w.dahandle == C.K2H_INVALID_HANDLE {
+		return 0, newOpError("Writer.Seek", w.key, nil, ErrClosed)
+	}
+	abs, err := daSeek(w.dahandle, "Writer.Seek", w.key, w.offset, offset, whence)
+	if err != nil {
+		return 0, err
+	}
+	w.offset = abs
+	return abs, nil
+}
+
+// Close frees the k2hash direct access handle.
+func (w *valueWriter) Close() error {
+	return daFree(&w.dahandle, "Writer.Close", w.key)
+}
+
+/* -- direct access helpers -- */
+
+// daLength returns the length of the value of a direct access handle.
+func daLength(dahandle C.k2h_da_h, op string, key []byte) (int64, error) {
+	length, errno := C.k2h_da_get_length(dahandle)
+	if length < 0 {
+		return 0, newOpError(op, key, errno, nil)
+	}
+	return int64(length), nil
+}
+
+// daSeek returns the new offset in the same way as io.Seeker.
+func daSeek(dahandle C.k2h_da_h, op string, key []byte, current int64, offset int64, whence int) (int64, error) {
+	var abs int64
+	switch whence {
+	default:
+		return 0, fmt.Errorf("%w: invalid whence %v", ErrInvalid, whence)
+	case io.SeekStart:
+		abs = offset
+	case io.SeekCurrent:
+		abs = current + offset
+	case io.SeekEnd:
+		length, err := daLength(dahandle, op, key)
+		if err != nil {
+			return 0, err
+		}
+		abs = length + offset
+	}
+	if abs < 0 {
+		return 0, fmt.Errorf("%w: negative offset %v", ErrInvalid, abs)
+	}
+	return abs, nil
+}
+
+// daFree frees a direct access handle. It is safe to call daFree more than once.
+func daFree(dahandle *C.k2h_da_h, op string, key []byte) error {
+	if *dahandle == C.K2H_INVALID_HANDLE {
+		return nil
+	}
+	ok, errno := C.k2h_da_free(*dahandle)
+	*dahandle = C.K2H_INVALID_HANDLE
+	if !ok {
+		return newOpError(op, key, errno, nil)
+	}
+	return nil
+}
+
+// Local Variables:
+// c-basic-offset: 4
+// tab-width: 4
+// indent-tabs-mode: t
+// End:
+// vim600: noexpandtab sw=4 ts=4 fdm=marker
+// vim<600: noexpandtab sw=4 ts=4
@@ -0,0 +1,259 @@
+//
+// k2hash_go
+//
+// Copyright 2018 Yahoo Japan Corporation.
+//
+// Go driver for k2hash that is a NoSQL Key Value Store(KVS) library.
+// For k2hash, see https://github.com/yahoojapan/k2hash for the details.
+//
+// For the full copyright and license information, please view
+// the license file that was distributed with this source code.
+//
+// AUTHOR:   Hirotaka Wakabayashi
+// CREATE:   Sun, 18 Oct 2026
+// REVISION:
+//
+
+package k2hash
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// SweepStats holds statistics of the expired key sweeper.
+type SweepStats struct {
+	// Scanned is the total number of keys scanned.
+	Scanned uint64
+	// Removed is the total number of expired keys removed.
+	Removed uint64
+	// Runs is the number of completed passes.
+	Runs uint64
+	// LastRun is the start time of the last completed pass.
+	LastRun time.Time
+	// LastErr is the error of the last pass, if any.
+	LastErr error
+}
+
+// String returns a text representation of the object.
+func (s SweepStats) String() string {
+	return fmt.Sprintf("[%v, %v, %v, %v, %v]", s.Scanned, s.Removed, s.Runs, s.LastRun, s.LastErr)
+}
+
+// sweeper keeps the state of the expired key sweeper.
+type sweeper struct {
+	// pass serializes passes.
+	pass sync.Mutex
+	// mu protects the following fields.
+	mu sync.Mutex
+	// stats is the statistics.
+	stats SweepStats
+	// cancel stops the background goroutine.
+	cancel context.CancelFunc
+	// done is closed when the background goroutine exits.
+	done chan struct{}
+}
+
+// rateLimiter limits the rate of operations.
+type rateLimiter struct {
+	// interval is the minimum interval of operations. zero means no limit.
+	interval time.Duration
+	// next is the time when the next operation is allowed.
+	next time.Time
+}
+
+// newRateLimiter returns a new rateLimiter which allows rate operations per second.
+func newRateLimiter(rate int) *rateLimiter {
+	l := rateLimiter{}
+	if rate > 0 {
+		l.interval = time.Second / time.Duration(rate)
+	}
+	return &l
+}
+
+// wait waits until the next operation is allowed or ctx is done.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil || l.interval == 0 {
+		return err
+	}
+	now := time.Now()
+	if l.next.After(now) {
+		timer := time.NewTimer(l.next.Sub(now))
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	} else {
+		l.next = now
+	}
+	l.next = l.next.Add(l.interval)
+	return nil
+}
+
+// SweepStats returns statistics of the expired key sweeper.
+func (k2h *K2hash) SweepStats() SweepStats {
+	k2h.sweep.mu.Lock()
+	defer k2h.sweep.mu.Unlock()
+	return k2h.sweep.stats
+}
+
+// sweepBatchSize is the max number of expired keys which Sweep keeps in memory.
+const sweepBatchSize = 100
+
+// Sweep scans all keys and removes keys whose expire attribute has passed in the same way as the background
+// sweeper started by WithSweeper. The rate is limited by WithSweepRate. It returns the number of removed keys.
+// The expired keys are removed in batches of sweepBatchSize keys during the scan. The key where the scan stays
+// is left to the next batch, so that the scan isn't disturbed by the removal.
+// The expire attribute is read again just before each key is removed, and the key is skipped unless it is
+// still expired.
+func (k2h *K2hash) Sweep(ctx context.Context) (int, error) {
+	if err := k2h.checkWritable("Sweep", nil); err != nil {
+		return 0, err
+	}
+	k2h.sweep.pass.Lock()
+	defer k2h.sweep.pass.Unlock()
+	start := time.Now()
+	limiter := newRateLimiter(k2h.sweepRate)
+	var scanned, removed uint64
+
+	// 1. scan keys and remove expired ones in batches
+	batch := make([][]byte, 0, sweepBatchSize)
+	c, err := k2h.NewCursor()
+	if err == nil {
+		for c.Next() {
+			if err = limiter.wait(ctx); err != nil {
+				break
+			}
+			scanned++
+			var current []byte
+			if k2h.expired(c.Key(), start) {
+				current = c.Key()
+				batch = append(batch, current)
+			}
+			if len(batch) < sweepBatchSize {
+				continue
+			}
+			if current != nil {
+				batch = batch[:len(batch)-1]
+			}
+			var n uint64
+			n, err = k2h.removeExpired(ctx, limiter, batch)
+			removed += n
+			if err != nil {
+				break
+			}
+			batch = batch[:0]
+			if current != nil {
+				batch = append(batch, current)
+			}
+		}
+		if err == nil {
+			err = c.Err()
+		}
+		c.Close()
+	}
+
+	// 2. remove the rest
+	if err == nil {
+		var n uint64
+		n, err = k2h.removeExpired(ctx, limiter, batch)
+		removed += n
+	}
+
+	// 3. update stats
+	k2h.sweep.mu.Lock()
+	defer k2h.sweep.mu.Unlock()
+	k2h.sweep.stats.Scanned += scanned
+	k2h.sweep.stats.Removed += removed
+	k2h.sweep.stats.LastErr = err
+	if err == nil {
+		k2h.sweep.stats.Runs++
+		k2h.sweep.stats.LastRun = start
+	}
+	return int(removed), err
+}
+
+// removeExpired removes the keys which are still expired, and returns the number of removed keys.
+func (k2h *K2hash) removeExpired(ctx context.Context, limiter *rateLimiter, keys [][]byte) (uint64, error) {
+	var removed uint64
+	for _, key := range keys {
+		if err := limiter.wait(ctx); err != nil {
+			return removed, err
+		}
+		// The key may be set again by others after the scan.
+		if !k2h.expired(key, time.Now()) {
+			continue
+		}
+		if _, err := k2h.Remove(key); err != nil {
+			// The key may be removed or updated by others.
+			if !errors.Is(err, ErrNotFound) {
+				return removed, err
+			}
+			continue
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// expired returns true if the expire attribute of a key has passed at t.
+func (k2h *K2hash) expired(key []byte, t time.Time) bool {
+	_, val, err := k2h.findAttr("Sweep", key, attrExpire)
+	if err != nil {
+		return false
+	}
+	expire := decodeAttrTime(val)
+	return !expire.IsZero() && !expire.After(t)
+}
+
+// startSweeper starts the background sweeper.
+func (k2h *K2hash) startSweeper() {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	k2h.sweep.cancel = cancel
+	k2h.sweep.done = done
+	interval := k2h.sweepInterval
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				// The error is saved in stats.
+				k2h.Sweep(ctx)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// stopSweeper stops the background sweeper and waits for it to exit or ctx to be done.
+func (k2h *K2hash) stopSweeper(ctx context.Context) error {
+	if k2h.sweep.cancel == nil {
+		return nil
+	}
+	k2h.sweep.cancel()
+	select {
+	case <-k2h.sweep.done:
+		k2h.sweep.cancel = nil
+		k2h.sweep.done = nil
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Local Variables:
+// c-basic-offset: 4
+// tab-width: 4
+// indent-tabs-mode: t
+// End:
+// vim600: noexpandtab sw=4 ts=4 fdm=marker
+// vim<600: noexpandtab sw=4 ts=4
@@ -38,6 +38,9 @@ type TxParams struct {
 
 // BeginTx enables transaction.
 func (k2h *K2hash) BeginTx(file string, options ...func(*TxParams)) (bool, error) {
+	if err := k2h.checkOpen("BeginTx", nil); err != nil {
+		return false, err
+	}
 	params := TxParams{
 		prefix:             "",
 		params:             "",
@@ -47,6 +50,9 @@ func (k2h *K2hash) BeginTx(file string, options ...func(*TxParams)) (bool, error
 	for _, option := range options {
 		option(&params)
 	}
+	if params.expirationDuration < 0 {
+		return false, fmt.Errorf("%w: expiration duration must be positive", ErrInvalid)
+	}
 
 	// 3. remove
 	cFile := C.CString(file)
@@ -60,18 +66,21 @@ func (k2h *K2hash) BeginTx(file string, options ...func(*TxParams)) (bool, error
 	if params.expirationDuration != 0 {
 		expire = (*C.time_t)(&params.expirationDuration)
 	}
-	ok := C.k2h_enable_transaction_param_we(k2h.handle, cFile, (*C.uchar)(cPrefix), (C.size_t)(len(params.prefix)+1), (*C.uchar)(cParams), (C.size_t)(len(params.params)+1), expire)
+	ok, errno := C.k2h_enable_transaction_param_we(k2h.handle, cFile, (*C.uchar)(cPrefix), (C.size_t)(len(params.prefix)+1), (*C.uchar)(cParams), (C.size_t)(len(params.params)+1), expire)
 	if ok != true {
-		return false, fmt.Errorf("C.k2h_enable_transaction_param_we return false")
+		return false, newOpError("BeginTx", nil, errno, nil)
 	}
 	return true, nil
 }
 
 // StopTx disables transaction.
 func (k2h *K2hash) StopTx() (bool, error) {
-	ok := C.k2h_disable_transaction(k2h.handle)
+	if err := k2h.checkOpen("StopTx", nil); err != nil {
+		return false, err
+	}
+	ok, errno := C.k2h_disable_transaction(k2h.handle)
 	if ok != true {
-		return false, fmt.Errorf("C.k2h_disable_transaction return false")
+		return false, newOpError("StopTx", nil, errno, nil)
 	}
 	return true, nil
 }
@@ -84,49 +93,55 @@ func (k2h *K2hash) GetTxFileFD() int32 {
 
 // GetTxThreadPoolSize returns the number of thread pools.
 func (k2h *K2hash) GetTxThreadPoolSize() (int32, error) {
-	pool := C.k2h_get_transaction_thread_pool()
+	pool, errno := C.k2h_get_transaction_thread_pool()
 	if pool < 0 {
-		return 0, fmt.Errorf("C.k2h_get_transaction_thread_pool return error")
+		return 0, newOpError("GetTxThreadPoolSize", nil, errno, nil)
 	}
 	return (int32)(pool), nil
 }
 
 // SetTxThreadPoolSize set the number of thread pools.
 func (k2h *K2hash) SetTxThreadPoolSize(pool int32) (bool, error) {
-	ok := C.k2h_set_transaction_thread_pool((C.int)(pool))
+	ok, errno := C.k2h_set_transaction_thread_pool((C.int)(pool))
 	if ok == false {
-		return false, fmt.Errorf("C.k2h_set_transaction_thread_pool return false")
+		return false, newOpError("SetTxThreadPoolSize", nil, errno, nil)
 	}
 	return true, nil
 }
 
 // UnsetTxThreadPoolSize set the number of thread pools zero.
 func (k2h *K2hash) UnsetTxThreadPoolSize() (bool, error) {
-	ok := C.k2h_unset_transaction_thread_pool()
+	ok, errno := C.k2h_unset_transaction_thread_pool()
 	if ok == false {
-		return false, fmt.Errorf("C.k2h_unset_transaction_thread_pool return false")
+		return false, newOpError("UnsetTxThreadPoolSize", nil, errno, nil)
 	}
 	return true, nil
 }
 
 // LoadFromFile loads data from archive files.
 func (k2h *K2hash) LoadFromFile(file string, ignoreError bool) (bool, error) {
+	if err := k2h.checkWritable("LoadFromFile", nil); err != nil {
+		return false, err
+	}
 	cFile := C.CString(file)
 	defer C.free(unsafe.Pointer(cFile))
-	ok := C.k2h_load_archive(k2h.handle, cFile, (C._Bool)(ignoreError))
+	ok, errno := C.k2h_load_archive(k2h.handle, cFile, (C._Bool)(ignoreError))
 	if ok == false {
-		return false, fmt.Errorf("C.k2h_load_archive return false")
+		return false, newOpError("LoadFromFile", nil, errno, nil)
 	}
 	return true, nil
 }
 
 // DumpToFile saves data from a file as a serialized data.
 func (k2h *K2hash) DumpToFile(file string, ignoreError bool) (bool, error) {
+	if err := k2h.checkOpen("DumpToFile", nil); err != nil {
+		return false, err
+	}
 	cFile := C.CString(file)
 	defer C.free(unsafe.Pointer(cFile))
-	ok := C.k2h_put_archive(k2h.handle, cFile, (C._Bool)(ignoreError))
+	ok, errno := C.k2h_put_archive(k2h.handle, cFile, (C._Bool)(ignoreError))
 	if ok != true {
-		return false, fmt.Errorf("k2h_put_archive() returns false")
+		return false, newOpError("DumpToFile", nil, errno, nil)
 	}
 	return true, nil
 }
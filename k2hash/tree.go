@@ -0,0 +1,300 @@
+//
+// k2hash_go
+//
+// Copyright 2018 Yahoo Japan Corporation.
+//
+// Go driver for k2hash that is a NoSQL Key Value Store(KVS) library.
+// For k2hash, see https://github.com/yahoojapan/k2hash for the details.
+//
+// For the full copyright and license information, please view
+// the license file that was distributed with this source code.
+//
+// AUTHOR:   Hirotaka Wakabayashi
+// CREATE:   Sun, 18 Oct 2026
+// REVISION:
+//
+
+package k2hash
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// TreeParams is a parameter set of CopyTree and MoveTree.
+type TreeParams struct {
+	dryRun       bool
+	parent       interface{}
+	oldParent    interface{}
+	prefixNaming bool
+	separator    string
+}
+
+// TreeOp is a kind of changes made by CopyTree and MoveTree.
+type TreeOp int
+
+const (
+	// TreeCopy copies a key to a new key.
+	TreeCopy TreeOp = iota
+	// TreeRemove removes a key.
+	TreeRemove
+	// TreeLink adds a subkey to a key.
+	TreeLink
+	// TreeUnlink removes a subkey from a key.
+	TreeUnlink
+)
+
+// String returns a text representation of the object.
+func (op TreeOp) String() string {
+	switch op {
+	case TreeRemove:
+		return "remove"
+	case TreeLink:
+		return "link"
+	case TreeUnlink:
+		return "unlink"
+	default:
+		return "copy"
+	}
+}
+
+// TreeChange is a change made by CopyTree and MoveTree.
+type TreeChange struct {
+	// Op is the kind of the change.
+	Op TreeOp
+	// Key is the key which is written or removed.
+	Key []byte
+	// Ref is the source key of TreeCopy, or the subkey of TreeLink and TreeUnlink.
+	Ref []byte
+}
+
+// String returns a text representation of the object.
+func (c TreeChange) String() string {
+	return fmt.Sprintf("[%v, %q, %q]", c.Op, trimNull(c.Key), trimNull(c.Ref))
+}
+
+// treeEntry is a key to be copied by CopyTree.
+type treeEntry struct {
+	key    []byte
+	newKey []byte
+	e      *Entry
+}
+
+// CopyTree copies the key src to dst together with all its subkeys recursively, preserving the values,
+// the subkey lists, the expiration and the attributes. The copy of a subkey is named by dst, "/" and the name of
+// the subkey, so that "a/b" is copied to "c/a/b" when "a" is copied to "c". Use WithTreePrefixNaming to copy
+// only the subkeys named under src, like "a/b" to "c/b", and share the others.
+// It returns ErrExist without writing anything if a new key already exists.
+// Encrypted values are decrypted by the registered passphrases and encrypted with the default passphrase.
+// It returns the changes, or the changes made before an error occurs.
+func (k2h *K2hash) CopyTree(s interface{}, d interface{}, options ...func(*TreeParams)) ([]TreeChange, error) {
+	return k2h.copyTree("CopyTree", s, d, false, options...)
+}
+
+// MoveTree moves the key src to dst together with its subkeys recursively in the same way as CopyTree, and
+// removes the source keys which are copied. Keys outside the subtree which have src or a copied key as
+// a subkey are not updated except the old parent given by WithTreeOldParent.
+func (k2h *K2hash) MoveTree(s interface{}, d interface{}, options ...func(*TreeParams)) ([]TreeChange, error) {
+	return k2h.copyTree("MoveTree", s, d, true, options...)
+}
+
+// copyTree copies a subtree, and removes the source keys if move is true.
+func (k2h *K2hash) copyTree(op string, s interface{}, d interface{}, move bool, options ...func(*TreeParams)) ([]TreeChange, error) {
+	// 1. binary or text
+	src, err := toBytes(s)
+	if err != nil {
+		return nil, err
+	}
+	dst, err := toBytes(d)
+	if err != nil {
+		return nil, err
+	}
+	if err := k2h.checkWritable(op, src); err != nil {
+		return nil, err
+	}
+
+	// 2. set params
+	params := TreeParams{
+		dryRun:       false,
+		parent:       nil,
+		oldParent:    nil,
+		prefixNaming: false,
+		separator:    "/",
+	}
+	for _, option := range options {
+		option(&params)
+	}
+	if params.prefixNaming && params.separator == "" {
+		return nil, fmt.Errorf("%w: empty separator", ErrInvalid)
+	}
+	if !move && params.oldParent != nil {
+		return nil, fmt.Errorf("%w: old parent is only for MoveTree", ErrInvalid)
+	}
+	var parent, oldParent []byte
+	if params.parent != nil {
+		if parent, err = toBytes(params.parent); err != nil {
+			return nil, err
+		}
+		if !k2h.exists(parent) {
+			return nil, newOpError(op, parent, nil, ErrNotFound)
+		}
+	}
+	if params.oldParent != nil {
+		if oldParent, err = toBytes(params.oldParent); err != nil {
+			return nil, err
+		}
+		if !k2h.exists(oldParent) {
+			return nil, newOpError(op, oldParent, nil, ErrNotFound)
+		}
+	}
+	if !k2h.exists(src) {
+		return nil, newOpError(op, src, nil, ErrNotFound)
+	}
+
+	// 3. read the subtree
+	var entries []treeEntry
+	copied := make(map[string][]byte)
+	err = k2h.WalkSubKeys(src, func(path [][]byte, key []byte) error {
+		newKey, ok := treeKey(src, dst, key, []byte(params.separator), params.prefixNaming)
+		if !ok {
+			// shared by the copies
+			return SkipSubKeys
+		}
+		e, err := k2h.GetEntry(key)
+		if errors.Is(err, ErrNotFound) {
+			// removed or expired
+			return SkipSubKeys
+		} else if err != nil {
+			return err
+		}
+		if k2h.exists(newKey) {
+			return newOpError(op, newKey, nil, ErrExist)
+		}
+		entries = append(entries, treeEntry{key: key, newKey: newKey, e: e})
+		copied[string(key)] = newKey
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. changes
+	var changes []TreeChange
+	for _, t := range entries {
+		changes = append(changes, TreeChange{Op: TreeCopy, Key: t.newKey, Ref: t.key})
+	}
+	if parent != nil {
+		changes = append(changes, TreeChange{Op: TreeLink, Key: parent, Ref: dst})
+	}
+	if move {
+		for _, t := range entries {
+			changes = append(changes, TreeChange{Op: TreeRemove, Key: t.key})
+		}
+	}
+	if oldParent != nil {
+		changes = append(changes, TreeChange{Op: TreeUnlink, Key: oldParent, Ref: src})
+	}
+	if params.dryRun {
+		return changes, nil
+	}
+
+	// 5. apply changes in order. The copies come first, so that changes[i] is the copy of entries[i].
+	for i, c := range changes {
+		switch c.Op {
+		case TreeCopy:
+			err = k2h.copyTreeEntry(entries[i], copied)
+		case TreeLink:
+			err = k2h.linkSubKey(op, c.Key, c.Ref)
+		case TreeRemove:
+			_, err = k2h.Remove(c.Key)
+		case TreeUnlink:
+			err = k2h.unlinkSubKey(op, c.Key, c.Ref)
+		}
+		if err != nil {
+			return changes[:i], err
+		}
+	}
+	return changes, nil
+}
+
+// copyTreeEntry writes a copy of a key. The subkeys which are copied are replaced with the copies.
+func (k2h *K2hash) copyTreeEntry(t treeEntry, copied map[string][]byte) error {
+	skeys := make([][]byte, len(t.e.SubKeys))
+	for i, skey := range t.e.SubKeys {
+		if newKey, ok := copied[string(skey)]; ok {
+			skeys[i] = newKey
+		} else {
+			skeys[i] = skey
+		}
+	}
+	next := Entry{
+		Value:   t.e.Value,
+		SubKeys: skeys,
+		Attrs:   t.e.Attrs,
+		Expire:  t.e.Expire,
+	}
+	_, err := k2h.SetEntry(t.newKey, next)
+	return err
+}
+
+// linkSubKey adds a subkey to a key unless the key already has it.
+func (k2h *K2hash) linkSubKey(op string, key []byte, skey []byte) error {
+	skeys, err := k2h.subKeys(op, key)
+	if err != nil {
+		return err
+	}
+	for _, s := range skeys {
+		if bytes.Equal(s, skey) {
+			return nil
+		}
+	}
+	_, err = k2h.SetSubKeys(key, append(skeys, skey))
+	return err
+}
+
+// unlinkSubKey removes a subkey from a key without removing the subkey itself.
+func (k2h *K2hash) unlinkSubKey(op string, key []byte, skey []byte) error {
+	skeys, err := k2h.subKeys(op, key)
+	if err != nil {
+		return err
+	}
+	rest := make([][]byte, 0, len(skeys))
+	for _, s := range skeys {
+		if !bytes.Equal(s, skey) {
+			rest = append(rest, s)
+		}
+	}
+	if len(rest) == len(skeys) {
+		return nil
+	}
+	_, err = k2h.SetSubKeys(key, rest)
+	return err
+}
+
+// treeKey returns the name of the copy of key when src is copied to dst. The copy is named by dst, sep and key
+// unless prefixNaming is true. Otherwise, it returns false if key is outside of src, which means that key
+// doesn't begin with src and sep. The null termination of a key saved as a string is kept.
+func treeKey(src, dst, key, sep []byte, prefixNaming bool) ([]byte, bool) {
+	if bytes.Equal(key, src) {
+		return dst, true
+	}
+	newKey := append([]byte{}, trimNull(dst)...)
+	if !prefixNaming {
+		newKey = append(newKey, sep...)
+		return append(newKey, key...), true
+	}
+	name := trimNull(src)
+	if !bytes.HasPrefix(key, name) || !bytes.HasPrefix(key[len(name):], sep) {
+		return nil, false
+	}
+	return append(newKey, key[len(name):]...), true
+}
+
+// Local Variables:
+// c-basic-offset: 4
+// tab-width: 4
+// indent-tabs-mode: t
+// End:
+// vim600: noexpandtab sw=4 ts=4 fdm=marker
+// vim<600: noexpandtab sw=4 ts=4
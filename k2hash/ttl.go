@@ -0,0 +1,145 @@
+//
+// k2hash_go
+//
+// Copyright 2018 Yahoo Japan Corporation.
+//
+// Go driver for k2hash that is a NoSQL Key Value Store(KVS) library.
+// For k2hash, see https://github.com/yahoojapan/k2hash for the details.
+//
+// For the full copyright and license information, please view
+// the license file that was distributed with this source code.
+//
+// AUTHOR:   Hirotaka Wakabayashi
+// CREATE:   Sun, 18 Oct 2026
+// REVISION:
+//
+
+package k2hash
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// NoExpiration is returned by TTL if a key never expires.
+const NoExpiration time.Duration = -1
+
+// TTL returns the time to live of a key, which is read from the expire attribute. It returns NoExpiration
+// if the key never expires, or ErrNotFound if the key doesn't exist.
+func (k2h *K2hash) TTL(k interface{}) (time.Duration, error) {
+	// 1. binary or text
+	key, err := toBytes(k)
+	if err != nil {
+		return 0, err
+	}
+	if err := k2h.checkOpen("TTL", key); err != nil {
+		return 0, err
+	}
+	if !k2h.exists(key) {
+		return 0, newOpError("TTL", key, nil, ErrNotFound)
+	}
+	// 2. read the expire attribute
+	_, val, err := k2h.findAttr("TTL", key, attrExpire)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return NoExpiration, nil
+		}
+		return 0, err
+	}
+	expire := decodeAttrTime(val)
+	if expire.IsZero() {
+		return NoExpiration, nil
+	}
+	if d := time.Until(expire); d > 0 {
+		return d, nil
+	}
+	return 0, nil
+}
+
+// ExpireAt sets the expiration time of a key. The key is rewritten in the same way as RemoveAttr, so that
+// an encrypted value needs WithPassword. A time which is not after now removes the key, because it expires at once.
+func (k2h *K2hash) ExpireAt(k interface{}, t time.Time, options ...func(*Params)) (bool, error) {
+	if t.IsZero() {
+		return false, fmt.Errorf("%w: zero expiration time", ErrInvalid)
+	}
+	return k2h.expireAt("ExpireAt", k, t, options)
+}
+
+// Touch extends the expiration time of a key to d later from now. The key is rewritten in the same way as ExpireAt.
+func (k2h *K2hash) Touch(k interface{}, d time.Duration, options ...func(*Params)) (bool, error) {
+	if d <= 0 {
+		return false, fmt.Errorf("%w: duration %v must be positive", ErrInvalid, d)
+	}
+	return k2h.expireAt("Touch", k, time.Now().Add(d), options)
+}
+
+// Persist removes the expiration time of a key, so that the key never expires. It does nothing if the key
+// never expires. The key is rewritten in the same way as RemoveAttr. A default expiration set by
+// SetExpirationDuration would be applied again by the rewrite, so that it returns ErrInvalid in that case
+// without rewriting the key.
+func (k2h *K2hash) Persist(k interface{}, options ...func(*Params)) (bool, error) {
+	// 1. binary or text
+	key, err := toBytes(k)
+	if err != nil {
+		return false, err
+	}
+	if err := k2h.checkWritable("Persist", key); err != nil {
+		return false, err
+	}
+	if d := k2h.defaultExpiration.Load(); d > 0 {
+		return false, newOpError("Persist", key, nil, fmt.Errorf("%w: the default expiration %vs is applied", ErrInvalid, d))
+	}
+	if !k2h.exists(key) {
+		return false, newOpError("Persist", key, nil, ErrNotFound)
+	}
+	// 2. remove the expire attribute
+	name, _, err := k2h.findAttr("Persist", key, attrExpire)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return true, nil
+		}
+		return false, err
+	}
+	return k2h.rewriteAttrs("Persist", key, name, nil, options)
+}
+
+// expireAt replaces the expire attribute of a key by rewriting the key in the same way as SetAttr.
+func (k2h *K2hash) expireAt(op string, k interface{}, t time.Time, options []func(*Params)) (bool, error) {
+	// 1. binary or text
+	key, err := toBytes(k)
+	if err != nil {
+		return false, err
+	}
+	if err := k2h.checkWritable(op, key); err != nil {
+		return false, err
+	}
+	if !k2h.exists(key) {
+		return false, newOpError(op, key, nil, ErrNotFound)
+	}
+	// 2. a key expired at once is removed
+	if !t.After(time.Now()) {
+		return k2h.Remove(key)
+	}
+	// 3. rewrite the key with the expire attribute
+	name, _ := toBytes(attrExpire)
+	return k2h.rewriteAttrs(op, key, name, encodeAttrTime(t), options)
+}
+
+// encodeAttrTime encodes a time in the same format as the builtin attribute plugin, which is a struct timespec
+// in the host byte order.
+func encodeAttrTime(t time.Time) []byte {
+	b := make([]byte, 16)
+	binary.NativeEndian.PutUint64(b[0:8], uint64(t.Unix()))
+	binary.NativeEndian.PutUint64(b[8:16], uint64(t.Nanosecond()))
+	return b
+}
+
+// Local Variables:
+// c-basic-offset: 4
+// tab-width: 4
+// indent-tabs-mode: t
+// End:
+// vim600: noexpandtab sw=4 ts=4 fdm=marker
+// vim<600: noexpandtab sw=4 ts=4
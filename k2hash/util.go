@@ -0,0 +1,162 @@
+//
+// k2hash_go
+//
+// Copyright 2018 Yahoo Japan Corporation.
+//
+// Go driver for k2hash that is a NoSQL Key Value Store(KVS) library.
+// For k2hash, see https://github.com/yahoojapan/k2hash for the details.
+//
+// For the full copyright and license information, please view
+// the license file that was distributed with this source code.
+//
+// AUTHOR:   Hirotaka Wakabayashi
+// CREATE:   Sun, 18 Oct 2026
+// REVISION:
+//
+
+package k2hash
+
+import (
+	// #cgo CFLAGS: -g -O2 -Wall -Wextra -Wno-unused-variable -Wno-unused-parameter -I. -I/usr/include/k2hash
+	// #cgo LDFLAGS: -L/usr/lib -lk2hash
+	// #include <stdlib.h>
+	// #include "k2hash.h"
+	"C"
+)
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// toBytes converts a key or a value to the byte sequence saved in a k2hash file.
+// A string is terminated with a null byte in the same way as the k2hash C string API does,
+// so that the data is compatible with the other k2hash tools. A []byte is saved as it is.
+func toBytes(v interface{}) ([]byte, error) {
+	switch v := v.(type) {
+	default:
+		return nil, fmt.Errorf("%w: unsupported data format %T", ErrInvalid, v)
+	case string:
+		return append([]byte(v), 0), nil
+	case []byte:
+		return v, nil
+	}
+}
+
+// toBytesSlice converts a list of keys to a slice of byte sequences in the same way as toBytes.
+func toBytesSlice(v interface{}) ([][]byte, error) {
+	switch v := v.(type) {
+	default:
+		return nil, fmt.Errorf("%w: unsupported data format %T", ErrInvalid, v)
+	case []string:
+		s := make([][]byte, len(v))
+		for i, k := range v {
+			s[i] = append([]byte(k), 0)
+		}
+		return s, nil
+	case [][]byte:
+		return v, nil
+	}
+}
+
+// trimNull removes a null termination at the end of data if it exists.
+func trimNull(b []byte) []byte {
+	if len(b) > 0 && b[len(b)-1] == 0 {
+		return b[:len(b)-1]
+	}
+	return b
+}
+
+// cBytes copies data to the C heap and returns the pointer and the length of it.
+// It returns a nil pointer if data is empty. The caller must free the pointer.
+func cBytes(b []byte) (*C.uchar, C.size_t) {
+	if len(b) == 0 {
+		return nil, 0
+	}
+	return (*C.uchar)(C.CBytes(b)), C.size_t(len(b))
+}
+
+// goBytes copies data in the C heap to a new byte slice. It never returns nil.
+func goBytes(p *C.uchar, length C.size_t) []byte {
+	if p == nil || length == 0 {
+		return []byte{}
+	}
+	return C.GoBytes(unsafe.Pointer(p), C.int(length))
+}
+
+// newKeyPack copies keys to a K2HKEYPCK array in the C heap. The caller must free it by freeKeyPack.
+func newKeyPack(keys [][]byte) C.PK2HKEYPCK {
+	if len(keys) == 0 {
+		return nil
+	}
+	count := len(keys)
+	pack := (C.PK2HKEYPCK)(C.calloc(C.size_t(count), C.size_t(unsafe.Sizeof(C.K2HKEYPCK{}))))
+	slice := (*[1 << 28]C.K2HKEYPCK)(unsafe.Pointer(pack))[:count:count]
+	for i, k := range keys {
+		slice[i].pkey, slice[i].length = cBytes(k)
+	}
+	return pack
+}
+
+// newAttrPack copies attribute names and values to a K2HATTRPCK array in the C heap.
+// The caller must free it by freeAttrPack.
+func newAttrPack(names, vals [][]byte) C.PK2HATTRPCK {
+	if len(names) == 0 {
+		return nil
+	}
+	count := len(names)
+	pack := (C.PK2HATTRPCK)(C.calloc(C.size_t(count), C.size_t(unsafe.Sizeof(C.K2HATTRPCK{}))))
+	slice := (*[1 << 28]C.K2HATTRPCK)(unsafe.Pointer(pack))[:count:count]
+	for i := range names {
+		slice[i].pkey, slice[i].keylength = cBytes(names[i])
+		slice[i].pval, slice[i].vallength = cBytes(vals[i])
+	}
+	return pack
+}
+
+// freeAttrPack frees a K2HATTRPCK array allocated by newAttrPack.
+func freeAttrPack(pack C.PK2HATTRPCK, count int) {
+	if pack == nil {
+		return
+	}
+	slice := (*[1 << 28]C.K2HATTRPCK)(unsafe.Pointer(pack))[:count:count]
+	for _, data := range slice {
+		C.free(unsafe.Pointer(data.pkey))
+		C.free(unsafe.Pointer(data.pval))
+	}
+	C.free(unsafe.Pointer(pack))
+}
+
+// goKeys copies keys in a K2HKEYPCK array to a slice of byte sequences. It never returns nil.
+func goKeys(pack C.PK2HKEYPCK, count C.int) [][]byte {
+	if pack == nil || count <= 0 {
+		return [][]byte{}
+	}
+	length := int(count)
+	slice := (*[1 << 28]C.K2HKEYPCK)(unsafe.Pointer(pack))[:length:length]
+	keys := make([][]byte, length)
+	for i, data := range slice {
+		keys[i] = goBytes(data.pkey, data.length)
+	}
+	return keys
+}
+
+// freeKeyPack frees a K2HKEYPCK array allocated by newKeyPack.
+func freeKeyPack(pack C.PK2HKEYPCK, count int) {
+	if pack == nil {
+		return
+	}
+	slice := (*[1 << 28]C.K2HKEYPCK)(unsafe.Pointer(pack))[:count:count]
+	for _, data := range slice {
+		C.free(unsafe.Pointer(data.pkey))
+	}
+	C.free(unsafe.Pointer(pack))
+}
+
+// Local Variables:
+// c-basic-offset: 4
+// tab-width: 4
+// indent-tabs-mode: t
+// End:
+// vim600: noexpandtab sw=4 ts=4 fdm=marker
+// vim<600: noexpandtab sw=4 ts=4
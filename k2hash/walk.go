@@ -0,0 +1,151 @@
+//
+// k2hash_go
+//
+// Copyright 2018 Yahoo Japan Corporation.
+//
+// Go driver for k2hash that is a NoSQL Key Value Store(KVS) library.
+// For k2hash, see https://github.com/yahoojapan/k2hash for the details.
+//
+// For the full copyright and license information, please view
+// the license file that was distributed with this source code.
+//
+// AUTHOR:   Hirotaka Wakabayashi
+// CREATE:   Sun, 18 Oct 2026
+// REVISION:
+//
+
+package k2hash
+
+import (
+	"errors"
+	"slices"
+)
+
+// WalkMode is the order of keys visited by WalkSubKeys.
+type WalkMode int
+
+const (
+	// WalkDFS visits keys in depth first order. A key is visited before its subkeys.
+	WalkDFS WalkMode = iota
+	// WalkBFS visits keys in breadth first order.
+	WalkBFS
+)
+
+// String returns a text representation of the object.
+func (m WalkMode) String() string {
+	switch m {
+	case WalkBFS:
+		return "bfs"
+	default:
+		return "dfs"
+	}
+}
+
+// SkipSubKeys is returned by the function of WalkSubKeys to skip the subkeys of the key.
+// It is not returned as an error by WalkSubKeys.
+var SkipSubKeys = errors.New("k2hash: skip subkeys")
+
+// WalkParams is a parameter set of WalkSubKeys.
+type WalkParams struct {
+	maxDepth int
+	mode     WalkMode
+}
+
+// walkNode is a key to be visited by WalkSubKeys.
+type walkNode struct {
+	path [][]byte
+	key  []byte
+}
+
+// WalkSubKeys calls fn for the root and its subkeys recursively. path is the keys from the root to
+// the parent of key, so that it is empty for the root. Each call gets its own path, so that fn may keep or modify
+// it, but the keys in it are shared and must not be modified. Subkeys form an arbitrary graph, so that each key
+// is visited only once even if it is a subkey of many keys or a cycle exists. A subkey which doesn't exist
+// is visited without its subkeys. WalkSubKeys stops and returns the error if fn returns an error except
+// SkipSubKeys. A key saved as a string contains the null termination.
+func (k2h *K2hash) WalkSubKeys(r interface{}, fn func(path [][]byte, key []byte) error, options ...func(*WalkParams)) error {
+	// 1. binary or text
+	root, err := toBytes(r)
+	if err != nil {
+		return err
+	}
+	if err := k2h.checkOpen("WalkSubKeys", root); err != nil {
+		return err
+	}
+
+	// 2. set params
+	params := WalkParams{
+		maxDepth: -1,
+		mode:     WalkDFS,
+	}
+	for _, option := range options {
+		option(&params)
+	}
+	if !k2h.exists(root) {
+		return newOpError("WalkSubKeys", root, nil, ErrNotFound)
+	}
+
+	// 3. walk. A key is marked as visited when it is queued in BFS, but when it is popped in DFS,
+	// because a key queued by a parent may be reached earlier through a sibling's subkeys.
+	visited := map[string]bool{}
+	if params.mode == WalkBFS {
+		visited[string(root)] = true
+	}
+	nodes := []walkNode{{path: [][]byte{}, key: root}}
+	for len(nodes) > 0 {
+		var n walkNode
+		if params.mode == WalkBFS {
+			n, nodes = nodes[0], nodes[1:]
+		} else {
+			n, nodes = nodes[len(nodes)-1], nodes[:len(nodes)-1]
+			if visited[string(n.key)] {
+				continue
+			}
+			visited[string(n.key)] = true
+		}
+		// fn gets a copy of the path because it may keep or modify it
+		if err := fn(slices.Clone(n.path), n.key); errors.Is(err, SkipSubKeys) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		if params.maxDepth >= 0 && len(n.path) >= params.maxDepth {
+			continue
+		}
+		skeys, err := k2h.subKeys("WalkSubKeys", n.key)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		// 4. the children share the path, which is never modified
+		path := append(n.path[:len(n.path):len(n.path)], n.key)
+		var children []walkNode
+		for _, skey := range skeys {
+			if visited[string(skey)] {
+				continue
+			}
+			if params.mode == WalkBFS {
+				visited[string(skey)] = true
+			}
+			children = append(children, walkNode{path: path, key: skey})
+		}
+		if params.mode == WalkBFS {
+			nodes = append(nodes, children...)
+		} else {
+			// the first subkey is visited first
+			for i := len(children) - 1; i >= 0; i-- {
+				nodes = append(nodes, children[i])
+			}
+		}
+	}
+	return nil
+}
+
+// Local Variables:
+// c-basic-offset: 4
+// tab-width: 4
+// indent-tabs-mode: t
+// End:
+// vim600: noexpandtab sw=4 ts=4 fdm=marker
+// vim<600: noexpandtab sw=4 ts=4
@@ -0,0 +1,205 @@
+//
+// k2hash_go
+//
+// Copyright 2018 Yahoo Japan Corporation.
+//
+// Go driver for k2hash that is a NoSQL Key Value Store(KVS) library.
+// For k2hash, see https://github.com/yahoojapan/k2hash for the details.
+//
+// For the full copyright and license information, please view
+// the license file that was distributed with this source code.
+//
+// AUTHOR:   Hirotaka Wakabayashi
+// CREATE:   Sun, 18 Oct 2026
+// REVISION:
+//
+
+package k2hashtest
+
+import (
+	// #cgo CFLAGS: -g -Wall -O2 -Wall -Wextra -Wno-unused-variable -Wno-unused-parameter -I. -I/usr/include/k2hash
+	// #cgo LDFLAGS: -L/usr/lib -lk2hash
+	// #include <stdlib.h>
+	// #include "k2hash.h"
+	"C"
+)
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/yahoojapan/k2hash_go/k2hash"
+)
+
+// The actual test functions are in non-_test.go files
+// so that they can use cgo (import "C").
+// These wrappers are here for gotest to find.
+
+// testGetAttr tests k2hash.GetAttr.
+func testGetAttr(t *testing.T) {
+	f := "/tmp/test.k2h"
+	k, err := k2hash.NewK2hash(f, k2hash.WithRemoveFile(true))
+	if err != nil {
+		t.Errorf("k2hash.NewK2hash(%v) return err %v", f, err)
+		return
+	}
+	defer k.Close()
+	bval := []byte{0x00, 0xff, 0x00}
+	if ok, err := k.Set("attr_key", "attr_val"); !ok {
+		t.Errorf("k2hash.Set(attr_key) return false. want true. err %v", err)
+	}
+	// 1. no attributes
+	if _, err := k.GetAttr("attr_key", "attr_name"); !errors.Is(err, k2hash.ErrNotFound) {
+		t.Errorf("k2hash.GetAttr(attr_key, attr_name) return err %v, want ErrNotFound", err)
+	}
+	// 2. a binary value
+	if ok, err := k.AddAttr("attr_key", "attr_name", bval); !ok {
+		t.Errorf("k2hash.AddAttr(attr_key, attr_name) return false. want true. err %v", err)
+	}
+	for _, name := range []interface{}{"attr_name", []byte("attr_name")} {
+		if val, err := k.GetAttr("attr_key", name); err != nil || !bytes.Equal(val, bval) {
+			t.Errorf("k2hash.GetAttr(attr_key, %v) = (%v, %v), want %v", name, val, err, bval)
+		}
+	}
+	// 3. no such attribute or key
+	if _, err := k.GetAttr("attr_key", "attr_noname"); !errors.Is(err, k2hash.ErrNotFound) {
+		t.Errorf("k2hash.GetAttr(attr_key, attr_noname) return err %v, want ErrNotFound", err)
+	}
+	if _, err := k.GetAttr("attr_nokey", "attr_name"); !errors.Is(err, k2hash.ErrNotFound) {
+		t.Errorf("k2hash.GetAttr(attr_nokey, attr_name) return err %v, want ErrNotFound", err)
+	}
+}
+
+// testSetAttr tests k2hash.SetAttr.
+func testSetAttr(t *testing.T) {
+	f := "/tmp/test.k2h"
+	k, err := k2hash.NewK2hash(f, k2hash.WithRemoveFile(true))
+	if err != nil {
+		t.Errorf("k2hash.NewK2hash(%v) return err %v", f, err)
+		return
+	}
+	defer k.Close()
+	if ok, err := k.Set("attr_key", "attr_val"); !ok {
+		t.Errorf("k2hash.Set(attr_key) return false. want true. err %v", err)
+	}
+	for i, val := range [][]byte{[]byte("attr_val1"), {0x00, 0x01}, []byte("attr_val3")} {
+		// The last one is named without the null termination.
+		var name interface{} = "attr_name"
+		if i == 2 {
+			name = []byte("attr_name")
+		}
+		if ok, err := k.SetAttr("attr_key", name, val); !ok {
+			t.Errorf("k2hash.SetAttr(attr_key, %v, %v) return false. want true. err %v", name, val, err)
+		}
+		if got, err := k.GetAttr("attr_key", "attr_name"); err != nil || !bytes.Equal(got, val) {
+			t.Errorf("k2hash.GetAttr(attr_key, attr_name) = (%v, %v), want %v", got, err, val)
+		}
+	}
+	if attrs, err := k.GetAttrs("attr_key"); err != nil || len(attrs) != 1 {
+		t.Errorf("k2hash.GetAttrs(attr_key) = (%v, %v), want 1 attribute", attrs, err)
+	}
+	if val, err := k.Get("attr_key"); err != nil || val.String() != "attr_val" {
+		t.Errorf("k2hash.Get(attr_key) = (%v, %v), want attr_val", val, err)
+	}
+	// The builtin attributes are maintained by the builtin attribute plugin.
+	if _, err := k.SetAttr("attr_key", "expire", []byte("attr_val")); !errors.Is(err, k2hash.ErrInvalid) {
+		t.Errorf("k2hash.SetAttr(attr_key, expire) return err %v, want ErrInvalid", err)
+	}
+}
+
+// testRemoveAttr tests k2hash.RemoveAttr.
+func testRemoveAttr(t *testing.T) {
+	f := "/tmp/test.k2h"
+	k, err := k2hash.NewK2hash(f, k2hash.WithRemoveFile(true))
+	if err != nil {
+		t.Errorf("k2hash.NewK2hash(%v) return err %v", f, err)
+		return
+	}
+	defer k.Close()
+	if ok, err := k.Set("attr_key", "attr_val"); !ok {
+		t.Errorf("k2hash.Set(attr_key) return false. want true. err %v", err)
+	}
+	if ok, err := k.SetSubKeys("attr_key", []string{"attr_subkey"}); !ok {
+		t.Errorf("k2hash.SetSubKeys(attr_key) return false. want true. err %v", err)
+	}
+	for _, name := range []string{"attr_name1", "attr_name2"} {
+		if ok, err := k.SetAttr("attr_key", name, []byte(name)); !ok {
+			t.Errorf("k2hash.SetAttr(attr_key, %v) return false. want true. err %v", name, err)
+		}
+	}
+	// 1. remove an attribute
+	if ok, err := k.RemoveAttr("attr_key", "attr_name1"); !ok {
+		t.Errorf("k2hash.RemoveAttr(attr_key, attr_name1) return false. want true. err %v", err)
+	}
+	if _, err := k.GetAttr("attr_key", "attr_name1"); !errors.Is(err, k2hash.ErrNotFound) {
+		t.Errorf("k2hash.GetAttr(attr_key, attr_name1) return err %v, want ErrNotFound", err)
+	}
+	// 2. the others are kept
+	if val, err := k.GetAttr("attr_key", "attr_name2"); err != nil || string(val) != "attr_name2" {
+		t.Errorf("k2hash.GetAttr(attr_key, attr_name2) = (%v, %v), want attr_name2", val, err)
+	}
+	if val, err := k.Get("attr_key"); err != nil || val.String() != "attr_val" {
+		t.Errorf("k2hash.Get(attr_key) = (%v, %v), want attr_val", val, err)
+	}
+	if skeys, err := k.GetSubKeys("attr_key"); err != nil || len(skeys) != 1 || skeys[0] != "attr_subkey" {
+		t.Errorf("k2hash.GetSubKeys(attr_key) = (%v, %v), want [attr_subkey]", skeys, err)
+	}
+	// 3. an encrypted value is written again with the password
+	if ok, err := k.Set("attr_enc_key", "attr_val", k2hash.WithPassword("attr_pass")); !ok {
+		t.Errorf("k2hash.Set(attr_enc_key) return false. want true. err %v", err)
+	}
+	if _, err := k.SetAttr("attr_enc_key", "attr_name1", []byte("attr_name1")); !errors.Is(err, k2hash.ErrInvalid) {
+		t.Errorf("k2hash.SetAttr(attr_enc_key, attr_name1) without a password return err %v, want ErrInvalid", err)
+	}
+	if ok, err := k.SetAttr("attr_enc_key", "attr_name1", []byte("attr_name1"), k2hash.WithPassword("attr_pass")); !ok {
+		t.Errorf("k2hash.SetAttr(attr_enc_key, attr_name1) return false. want true. err %v", err)
+	}
+	if _, err := k.RemoveAttr("attr_enc_key", "attr_name1"); !errors.Is(err, k2hash.ErrInvalid) {
+		t.Errorf("k2hash.RemoveAttr(attr_enc_key, attr_name1) without a password return err %v, want ErrInvalid", err)
+	}
+	if ok, err := k.RemoveAttr("attr_enc_key", "attr_name1", k2hash.WithPassword("attr_pass")); !ok {
+		t.Errorf("k2hash.RemoveAttr(attr_enc_key, attr_name1) return false. want true. err %v", err)
+	}
+	if val, err := k.Get("attr_enc_key", k2hash.WithPassword("attr_pass")); err != nil || val.String() != "attr_val" {
+		t.Errorf("k2hash.Get(attr_enc_key) = (%v, %v), want attr_val", val, err)
+	}
+	// 4. no such attribute or key
+	if _, err := k.RemoveAttr("attr_key", "attr_name1"); !errors.Is(err, k2hash.ErrNotFound) {
+		t.Errorf("k2hash.RemoveAttr(attr_key, attr_name1) return err %v, want ErrNotFound", err)
+	}
+	if _, err := k.RemoveAttr("attr_nokey", "attr_name1"); !errors.Is(err, k2hash.ErrNotFound) {
+		t.Errorf("k2hash.RemoveAttr(attr_nokey, attr_name1) return err %v, want ErrNotFound", err)
+	}
+	// 5. a staging key left by a crash is hidden from scans, and removed by the next rewrite
+	staging := []byte("\x00k2hash_go:staging:attr_key\x00")
+	if ok, err := k.Set(staging, "attr_partial"); !ok {
+		t.Errorf("k2hash.Set(%q) return false. want true. err %v", staging, err)
+		return
+	}
+	c := k.NewScanCursor()
+	defer c.Close()
+	results, err := c.Next(10)
+	if err != nil || len(results) != 2 {
+		t.Errorf("ScanCursor.Next(10) = (%v, %v), want 2 keys without the staging key", results, err)
+	}
+	for _, r := range results {
+		if bytes.Equal(r.Key, staging) {
+			t.Errorf("ScanCursor.Next(10) returns the staging key %q", r.Key)
+		}
+	}
+	if ok, err := k.RemoveAttr("attr_key", "attr_name2"); !ok {
+		t.Errorf("k2hash.RemoveAttr(attr_key, attr_name2) with a staging key return false. want true. err %v", err)
+	}
+	if _, err := k.Get(staging); !errors.Is(err, k2hash.ErrNotFound) {
+		t.Errorf("k2hash.Get(%q) return err %v, want ErrNotFound", staging, err)
+	}
+}
+
+// Local Variables:
+// c-basic-offset: 4
+// tab-width: 4
+// indent-tabs-mode: t
+// End:
+// vim600: noexpandtab sw=4 ts=4 fdm=marker
+// vim<600: noexpandtab sw=4 ts=4
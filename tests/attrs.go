@@ -0,0 +1,113 @@
+//
+// k2hash_go
+//
+// Copyright 2018 Yahoo Japan Corporation.
+//
+// Go driver for k2hash that is a NoSQL Key Value Store(KVS) library.
+// For k2hash, see https://github.com/yahoojapan/k2hash for the details.
+//
+// For the full copyright and license information, please view
+// the license file that was distributed with this source code.
+//
+// AUTHOR:   Hirotaka Wakabayashi
+// CREATE:   Sun, 18 Oct 2026
+// REVISION:
+//
+
+package k2hashtest
+
+import (
+	// #cgo CFLAGS: -g -Wall -O2 -Wall -Wextra -Wno-unused-variable -Wno-unused-parameter -I. -I/usr/include/k2hash
+	// #cgo LDFLAGS: -L/usr/lib -lk2hash
+	// #include <stdlib.h>
+	// #include "k2hash.h"
+	"C"
+)
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/yahoojapan/k2hash_go/k2hash"
+)
+
+// The actual test functions are in non-_test.go files
+// so that they can use cgo (import "C").
+// These wrappers are here for gotest to find.
+
+// testAttrs tests accessors of k2hash.Attrs.
+func testAttrs(t *testing.T) {
+	f := "/tmp/test.k2h"
+	k, err := k2hash.NewK2hash(f, k2hash.WithRemoveFile(true))
+	if err != nil {
+		t.Errorf("k2hash.NewK2hash(%v) return err %v", f, err)
+		return
+	}
+	defer k.Close()
+	// 1. raw values
+	bval := []byte{0x01, 0x00, 0x02, 0x00}
+	if ok, err := k.Set("attrs_key", "attrs_val"); !ok {
+		t.Errorf("k2hash.Set(attrs_key) return false. want true. err %v", err)
+	}
+	if ok, err := k.AddAttr("attrs_key", "attrs_bin", bval); !ok {
+		t.Errorf("k2hash.AddAttr(attrs_key, attrs_bin) return false. want true. err %v", err)
+	}
+	attrs, err := k.GetAttrs("attrs_key")
+	if err != nil {
+		t.Errorf("k2hash.GetAttrs(attrs_key) return err %v", err)
+		return
+	}
+	if val, ok := attrs.Get("attrs_bin"); !ok || !bytes.Equal(val, bval) {
+		t.Errorf("Attrs.Get(attrs_bin) = (%v, %v), want %v", val, ok, bval)
+	}
+	for _, a := range attrs {
+		if a.Name() == "attrs_bin" && !bytes.Equal(a.Value(), bval) {
+			t.Errorf("Attr.Value() = %v, want %v", a.Value(), bval)
+		}
+	}
+	if !attrs.Mtime().IsZero() || !attrs.Expire().IsZero() || attrs.HistoryUUID() != "" || attrs.IsEncrypted() {
+		t.Errorf("k2hash.GetAttrs(attrs_key) = %v, want no builtin attributes", attrs)
+	}
+	// 2. builtin attributes
+	if ok, err := k.EnableMtime(true); !ok {
+		t.Errorf("k2hash.EnableMtime(true) return false. want true. err %v", err)
+	}
+	if ok, err := k.EnableHistory(true); !ok {
+		t.Errorf("k2hash.EnableHistory(true) return false. want true. err %v", err)
+	}
+	now := time.Now()
+	for _, val := range []string{"attrs_val1", "attrs_val2"} {
+		if ok, err := k.Set("attrs_builtin", val, k2hash.WithExpire(time.Hour)); !ok {
+			t.Errorf("k2hash.Set(attrs_builtin) return false. want true. err %v", err)
+		}
+	}
+	if attrs, err = k.GetAttrs("attrs_builtin"); err != nil {
+		t.Errorf("k2hash.GetAttrs(attrs_builtin) return err %v", err)
+		return
+	}
+	if mtime := attrs.Mtime(); mtime.Before(now.Add(-time.Minute)) || mtime.After(now.Add(time.Minute)) {
+		t.Errorf("Attrs.Mtime() = %v, want about %v", mtime, now)
+	}
+	if expire, want := attrs.Expire(), now.Add(time.Hour); expire.Before(want.Add(-time.Minute)) || expire.After(want.Add(time.Minute)) {
+		t.Errorf("Attrs.Expire() = %v, want about %v", expire, want)
+	}
+	if attrs.HistoryUUID() == "" {
+		t.Errorf("Attrs.HistoryUUID() is empty, want a uuid")
+	}
+	// 3. encryption
+	if ok, err := k.Set("attrs_encrypted", "attrs_val", k2hash.WithPassword("attrs_pass")); !ok {
+		t.Errorf("k2hash.Set(attrs_encrypted) return false. want true. err %v", err)
+	}
+	if attrs, err := k.GetAttrs("attrs_encrypted"); err != nil || !attrs.IsEncrypted() {
+		t.Errorf("k2hash.GetAttrs(attrs_encrypted) = (%v, %v), want encrypted", attrs, err)
+	}
+}
+
+// Local Variables:
+// c-basic-offset: 4
+// tab-width: 4
+// indent-tabs-mode: t
+// End:
+// vim600: noexpandtab sw=4 ts=4 fdm=marker
+// vim<600: noexpandtab sw=4 ts=4
@@ -0,0 +1,232 @@
+//
+// k2hash_go
+//
+// Copyright 2018 Yahoo Japan Corporation.
+//
+// Go driver for k2hash that is a NoSQL Key Value Store(KVS) library.
+// For k2hash, see https://github.com/yahoojapan/k2hash for the details.
+//
+// For the full copyright and license information, please view
+// the license file that was distributed with this source code.
+//
+// AUTHOR:   Hirotaka Wakabayashi
+// CREATE:   Sun, 18 Oct 2026
+// REVISION:
+//
+
+package k2hashtest
+
+import (
+	// #cgo CFLAGS: -g -Wall -O2 -Wall -Wextra -Wno-unused-variable -Wno-unused-parameter -I. -I/usr/include/k2hash
+	// #cgo LDFLAGS: -L/usr/lib -lk2hash
+	// #include <stdlib.h>
+	// #include "k2hash.h"
+	"C"
+)
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/yahoojapan/k2hash_go/k2hash"
+)
+
+// The actual test functions are in non-_test.go files
+// so that they can use cgo (import "C").
+// These wrappers are here for gotest to find.
+
+// benchBatchSize is the number of records in a batch of benchmarks.
+const benchBatchSize = 1000
+
+// testBatch tests k2hash.SetMany, k2hash.GetMany and k2hash.RemoveMany.
+func testBatch(t *testing.T) {
+	f := "/tmp/test.k2h"
+	k, err := k2hash.NewK2hash(f, k2hash.WithRemoveFile(true))
+	if err != nil {
+		t.Errorf("k2hash.NewK2hash(%v) return err %v", f, err)
+		return
+	}
+	defer k.Close()
+	// 1. SetMany
+	keys := []string{"batch_key1", "batch_key2", "batch_key3"}
+	vals := []string{"batch_val1", "", "batch_val3"}
+	results, err := k.SetMany(keys, vals)
+	if err != nil || len(results) != len(keys) {
+		t.Errorf("k2hash.SetMany(%v) = (%v, %v), want %v results", keys, results, err, len(keys))
+		return
+	}
+	for i, r := range results {
+		if r.Err != nil {
+			t.Errorf("k2hash.SetMany(%v) return err %v", keys[i], r.Err)
+		}
+	}
+	// 2. GetMany
+	results, err = k.GetMany(append(keys, "batch_nokey"))
+	if err != nil || len(results) != len(keys)+1 {
+		t.Errorf("k2hash.GetMany(%v) = (%v, %v), want %v results", keys, results, err, len(keys)+1)
+		return
+	}
+	for i, r := range results[:len(keys)] {
+		if r.Err != nil || r.Value.String() != vals[i] {
+			t.Errorf("k2hash.GetMany(%v) = (%v, %v), want %v", keys[i], r.Value, r.Err, vals[i])
+		}
+	}
+	if r := results[len(keys)]; !errors.Is(r.Err, k2hash.ErrNotFound) || r.Value != nil {
+		t.Errorf("k2hash.GetMany(batch_nokey) = (%v, %v), want ErrNotFound", r.Value, r.Err)
+	}
+	// 3. binary
+	bkeys := [][]byte{{0x00, 0x01}, {0x02}}
+	bvals := [][]byte{{0xff, 0x00}, {}}
+	if _, err := k.SetMany(bkeys, bvals); err != nil {
+		t.Errorf("k2hash.SetMany(%v) return err %v", bkeys, err)
+	}
+	if results, err := k.GetMany(bkeys); err != nil || string(results[0].Value.Bytes()) != string(bvals[0]) {
+		t.Errorf("k2hash.GetMany(%v) = (%v, %v), want %v", bkeys, results, err, bvals)
+	}
+	// 4. RemoveMany
+	if results, err = k.RemoveMany(keys); err != nil {
+		t.Errorf("k2hash.RemoveMany(%v) return err %v", keys, err)
+	}
+	for i, r := range results {
+		if r.Err != nil {
+			t.Errorf("k2hash.RemoveMany(%v) return err %v", keys[i], r.Err)
+		}
+	}
+	if results, err = k.GetMany(keys); err != nil || !errors.Is(results[0].Err, k2hash.ErrNotFound) {
+		t.Errorf("k2hash.GetMany(%v) = (%v, %v), want ErrNotFound", keys, results, err)
+	}
+	// 5. invalid arguments
+	if _, err := k.SetMany(keys, vals[:1]); !errors.Is(err, k2hash.ErrInvalid) {
+		t.Errorf("k2hash.SetMany() with different lengths return err %v, want ErrInvalid", err)
+	}
+	if _, err := k.RemoveMany(keys, k2hash.WithRemoveSubKey("batch_subkey")); !errors.Is(err, k2hash.ErrInvalid) {
+		t.Errorf("k2hash.RemoveMany(WithRemoveSubKey) return err %v, want ErrInvalid", err)
+	}
+}
+
+// benchBatchData returns keys and values of benchmarks.
+func benchBatchData() ([]string, []string) {
+	keys := make([]string, benchBatchSize)
+	vals := make([]string, benchBatchSize)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("bench_key%v", i)
+		vals[i] = fmt.Sprintf("bench_val%v", i)
+	}
+	return keys, vals
+}
+
+// benchK2hash returns a k2hash database of benchmarks.
+func benchK2hash(b *testing.B) *k2hash.K2hash {
+	k, err := k2hash.NewMemoryK2hash()
+	if err != nil {
+		b.Fatalf("k2hash.NewMemoryK2hash() return err %v", err)
+	}
+	return k
+}
+
+// benchmarkSet sets a batch of values by k2hash.Set.
+func benchmarkSet(b *testing.B) {
+	k := benchK2hash(b)
+	defer k.Close()
+	keys, vals := benchBatchData()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for i := range keys {
+			if ok, err := k.Set(keys[i], vals[i]); !ok {
+				b.Fatalf("k2hash.Set(%v) return err %v", keys[i], err)
+			}
+		}
+	}
+}
+
+// benchmarkSetMany sets a batch of values by k2hash.SetMany.
+func benchmarkSetMany(b *testing.B) {
+	k := benchK2hash(b)
+	defer k.Close()
+	keys, vals := benchBatchData()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		if _, err := k.SetMany(keys, vals); err != nil {
+			b.Fatalf("k2hash.SetMany() return err %v", err)
+		}
+	}
+}
+
+// benchmarkGet gets a batch of values by k2hash.Get.
+func benchmarkGet(b *testing.B) {
+	k := benchK2hash(b)
+	defer k.Close()
+	keys, vals := benchBatchData()
+	if _, err := k.SetMany(keys, vals); err != nil {
+		b.Fatalf("k2hash.SetMany() return err %v", err)
+	}
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for _, key := range keys {
+			if _, err := k.Get(key); err != nil {
+				b.Fatalf("k2hash.Get(%v) return err %v", key, err)
+			}
+		}
+	}
+}
+
+// benchmarkGetMany gets a batch of values by k2hash.GetMany.
+func benchmarkGetMany(b *testing.B) {
+	k := benchK2hash(b)
+	defer k.Close()
+	keys, vals := benchBatchData()
+	if _, err := k.SetMany(keys, vals); err != nil {
+		b.Fatalf("k2hash.SetMany() return err %v", err)
+	}
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		if _, err := k.GetMany(keys); err != nil {
+			b.Fatalf("k2hash.GetMany() return err %v", err)
+		}
+	}
+}
+
+// benchmarkRemove removes a batch of keys by k2hash.Remove.
+func benchmarkRemove(b *testing.B) {
+	k := benchK2hash(b)
+	defer k.Close()
+	keys, vals := benchBatchData()
+	for n := 0; n < b.N; n++ {
+		b.StopTimer()
+		if _, err := k.SetMany(keys, vals); err != nil {
+			b.Fatalf("k2hash.SetMany() return err %v", err)
+		}
+		b.StartTimer()
+		for _, key := range keys {
+			if ok, err := k.Remove(key); !ok {
+				b.Fatalf("k2hash.Remove(%v) return err %v", key, err)
+			}
+		}
+	}
+}
+
+// benchmarkRemoveMany removes a batch of keys by k2hash.RemoveMany.
+func benchmarkRemoveMany(b *testing.B) {
+	k := benchK2hash(b)
+	defer k.Close()
+	keys, vals := benchBatchData()
+	for n := 0; n < b.N; n++ {
+		b.StopTimer()
+		if _, err := k.SetMany(keys, vals); err != nil {
+			b.Fatalf("k2hash.SetMany() return err %v", err)
+		}
+		b.StartTimer()
+		if _, err := k.RemoveMany(keys); err != nil {
+			b.Fatalf("k2hash.RemoveMany() return err %v", err)
+		}
+	}
+}
+
+// Local Variables:
+// c-basic-offset: 4
+// tab-width: 4
+// indent-tabs-mode: t
+// End:
+// vim600: noexpandtab sw=4 ts=4 fdm=marker
+// vim<600: noexpandtab sw=4 ts=4
@@ -0,0 +1,124 @@
+//
+// k2hash_go
+//
+// Copyright 2018 Yahoo Japan Corporation.
+//
+// Go driver for k2hash that is a NoSQL Key Value Store(KVS) library.
+// For k2hash, see https://github.com/yahoojapan/k2hash for the details.
+//
+// For the full copyright and license information, please view
+// the license file that was distributed with this source code.
+//
+// AUTHOR:   Hirotaka Wakabayashi
+// CREATE:   Sun, 18 Oct 2026
+// REVISION:
+//
+
+package k2hashtest
+
+import (
+	// #cgo CFLAGS: -g -Wall -O2 -Wall -Wextra -Wno-unused-variable -Wno-unused-parameter -I. -I/usr/include/k2hash
+	// #cgo LDFLAGS: -L/usr/lib -lk2hash
+	// #include <stdlib.h>
+	// #include "k2hash.h"
+	"C"
+)
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/yahoojapan/k2hash_go/k2hash"
+)
+
+// The actual test functions are in non-_test.go files
+// so that they can use cgo (import "C").
+// These wrappers are here for gotest to find.
+
+// testSetGetBinary tests k2hash.Set and k2hash.Get methods with binary data.
+func testSetGetBinary(t *testing.T) {
+	// 1. define test data.
+	testData := []kv{
+		{
+			d: "binary data with null bytes",
+			k: []byte("bin\x00key"),
+			v: []byte("bin\x00val\x00\x01\x02"),
+			s: false,
+			p: "",
+			e: 0,
+		},
+		{
+			d: "binary data which ends with a null byte",
+			k: []byte("bin_null_key"),
+			v: []byte{0x0a, 0x01, 0x00},
+			s: false,
+			p: "",
+			e: 0,
+		},
+		{
+			d: "empty binary data",
+			k: []byte("bin_empty_key"),
+			v: []byte{},
+			s: false,
+			p: "",
+			e: 0,
+		},
+	}
+	k, err := k2hash.NewK2hash("/tmp/test.k2h")
+	if err != nil {
+		t.Errorf("k2hash.NewK2hash(/tmp/test.k2h) return err %v", err)
+	}
+	defer k.Close()
+	for _, d := range testData {
+		if ok, err := k.Set(d.k, d.v); !ok {
+			t.Errorf("k2hash.Set(%v, %v) return false. want true. err %v", d.k, d.v, err)
+		}
+		val, err := k.Get(d.k)
+		if err != nil {
+			t.Errorf("k2hash.Get(%v) return err %v", d.k, err)
+			continue
+		}
+		if !bytes.Equal(val.Bytes(), d.v) {
+			t.Errorf("k2hash.Get(%v).Bytes() = %v, want %v", d.k, val.Bytes(), d.v)
+		}
+		if ok, err := k.Remove(d.k); !ok {
+			t.Errorf("k2hash.Remove(%v) return false. want true. err %v", d.k, err)
+		}
+	}
+	// a value saved as a string
+	if ok, err := k.Set("bin_str_key", "bin_str_val"); !ok {
+		t.Errorf("k2hash.Set(bin_str_key) return false. want true. err %v", err)
+	}
+	if val, err := k.Get("bin_str_key"); err != nil || val.String() != "bin_str_val" || string(val.Bytes()) != "bin_str_val\x00" {
+		t.Errorf("k2hash.Get(bin_str_key) = (%v, %v), want String without and Bytes with the null termination", val, err)
+	}
+}
+
+// testQueueBinary tests Queue.Push and Queue.PopBytes methods with binary data.
+func testQueueBinary(t *testing.T) {
+	k, err := k2hash.NewK2hash("/tmp/test.k2h")
+	if err != nil {
+		t.Errorf("k2hash.NewK2hash(/tmp/test.k2h) return err %v", err)
+	}
+	defer k.Close()
+	q, err := k2hash.NewQueue(k)
+	if err != nil {
+		t.Errorf("k2hash.NewQueue(%v) return err %v", k, err)
+	}
+	defer q.Free()
+	v := []byte("queue\x00val")
+	if ok, err := q.Push(v); !ok {
+		t.Errorf("Queue.Push(%v) return false. wants true. err %v", v, err)
+	}
+	if b, err := q.PopBytes(); !bytes.Equal(b, v) {
+		t.Errorf("Queue.PopBytes() = (%v, %v), want %v", b, err, v)
+	}
+}
+
+// Local Variables:
+// c-basic-offset: 4
+// tab-width: 4
+// indent-tabs-mode: t
+// End:
+// vim600: noexpandtab sw=4 ts=4 fdm=marker
+// vim<600: noexpandtab sw=4 ts=4
@@ -0,0 +1,299 @@
+//
+// k2hash_go
+//
+// Copyright 2018 Yahoo Japan Corporation.
+//
+// Go driver for k2hash that is a NoSQL Key Value Store(KVS) library.
+// For k2hash, see https://github.com/yahoojapan/k2hash for the details.
+//
+// For the full copyright and license information, please view
+// the license file that was distributed with this source code.
+//
+// AUTHOR:   Hirotaka Wakabayashi
+// CREATE:   Sun, 18 Oct 2026
+// REVISION:
+//
+
+package k2hashtest
+
+import (
+	// #cgo CFLAGS: -g -Wall -O2 -Wall -Wextra -Wno-unused-variable -Wno-unused-parameter -I. -I/usr/include/k2hash
+	// #cgo LDFLAGS: -L/usr/lib -lk2hash
+	// #include <stdlib.h>
+	// #include "k2hash.h"
+	"C"
+)
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/yahoojapan/k2hash_go/k2hash"
+)
+
+// The actual test functions are in non-_test.go files
+// so that they can use cgo (import "C").
+// These wrappers are here for gotest to find.
+
+// testContext tests the context variants of k2hash methods.
+func testContext(t *testing.T) {
+	f := "/tmp/test.k2h"
+	k, err := k2hash.NewK2hash(f, k2hash.WithRemoveFile(true))
+	if err != nil {
+		t.Errorf("k2hash.NewK2hash(%v) return err %v", f, err)
+		return
+	}
+	defer k.Close()
+	ctx := context.Background()
+	// 1. a live context
+	if ok, err := k.SetContext(ctx, "context_key", "context_val"); !ok {
+		t.Errorf("k2hash.SetContext(context_key) return false. want true. err %v", err)
+	}
+	if val, err := k.GetContext(ctx, "context_key"); err != nil || val.String() != "context_val" {
+		t.Errorf("k2hash.GetContext(context_key) = (%v, %v), want context_val", val, err)
+	}
+	dump := "/tmp/test_context.dump"
+	defer os.Remove(dump)
+	if ok, err := k.DumpToFileContext(ctx, dump, false); !ok {
+		t.Errorf("k2hash.DumpToFileContext(%v) return false. want true. err %v", dump, err)
+	}
+	if ok, err := k.RemoveContext(ctx, "context_key"); !ok {
+		t.Errorf("k2hash.RemoveContext(context_key) return false. want true. err %v", err)
+	}
+	if ok, err := k.LoadFromFileContext(ctx, dump, false); !ok {
+		t.Errorf("k2hash.LoadFromFileContext(%v) return false. want true. err %v", dump, err)
+	}
+	if val, err := k.Get("context_key"); err != nil || val.String() != "context_val" {
+		t.Errorf("k2hash.Get(context_key) = (%v, %v), want context_val", val, err)
+	}
+	// 2. an expired context
+	expired, cancel := context.WithDeadline(ctx, time.Now().Add(-time.Second))
+	defer cancel()
+	if _, err := k.GetContext(expired, "context_key"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("k2hash.GetContext(context_key) return err %v, want context.DeadlineExceeded", err)
+	}
+	if _, err := k.SetContext(expired, "context_key", "context_val2"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("k2hash.SetContext(context_key) return err %v, want context.DeadlineExceeded", err)
+	}
+	if val, err := k.Get("context_key"); err != nil || val.String() != "context_val" {
+		t.Errorf("k2hash.Get(context_key) = (%v, %v), want context_val", val, err)
+	}
+	// 3. a canceled context
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := k.DumpToFileContext(canceled, dump, false); !errors.Is(err, context.Canceled) {
+		t.Errorf("k2hash.DumpToFileContext(%v) return err %v, want context.Canceled", dump, err)
+	}
+}
+
+// testQueueContext tests the context variants of queue methods.
+func testQueueContext(t *testing.T) {
+	f := "/tmp/test.k2h"
+	k, err := k2hash.NewK2hash(f, k2hash.WithRemoveFile(true))
+	if err != nil {
+		t.Errorf("k2hash.NewK2hash(%v) return err %v", f, err)
+		return
+	}
+	defer k.Close()
+	q, err := k2hash.NewQueue(k)
+	if err != nil {
+		t.Errorf("k2hash.NewQueue() return err %v", err)
+		return
+	}
+	defer q.Free()
+	ctx := context.Background()
+	if ok, err := q.PushContext(ctx, "context_val"); !ok {
+		t.Errorf("Queue.PushContext(context_val) return false. want true. err %v", err)
+	}
+	expired, cancel := context.WithTimeout(ctx, 0)
+	defer cancel()
+	if _, err := q.PopContext(expired); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Queue.PopContext() return err %v, want context.DeadlineExceeded", err)
+	}
+	if val, err := q.PopContext(ctx); err != nil || val != "context_val" {
+		t.Errorf("Queue.PopContext() = (%v, %v), want context_val", val, err)
+	}
+	if _, err := q.PopContext(ctx); !errors.Is(err, k2hash.ErrQueueEmpty) {
+		t.Errorf("Queue.PopContext() return err %v, want ErrQueueEmpty", err)
+	}
+}
+
+// testContextAbandon tests an operation abandoned while it is running.
+func testContextAbandon(t *testing.T) {
+	// 1. GetContext blocks in the key provider while it reloads the passphrases on a decrypt failure
+	var blocking atomic.Bool
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	provider := k2hash.KeyProviderFunc(func(ctx context.Context) ([][]byte, error) {
+		if blocking.Load() {
+			close(entered)
+			<-release
+		}
+		return [][]byte{[]byte("abandon_pass")}, nil
+	})
+	f := "/tmp/test.k2h"
+	k, err := k2hash.NewK2hash(f, k2hash.WithRemoveFile(true), k2hash.WithKeyProvider(provider))
+	if err != nil {
+		t.Errorf("k2hash.NewK2hash(%v) return err %v", f, err)
+		return
+	}
+	if ok, err := k.Set("abandon_key", "abandon_val", k2hash.WithPassword("abandon_other")); !ok {
+		t.Errorf("k2hash.Set(abandon_key) return false. want true. err %v", err)
+	}
+	blocking.Store(true)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-entered
+		cancel()
+	}()
+	if _, err := k.GetContext(ctx, "abandon_key"); !errors.Is(err, context.Canceled) {
+		t.Errorf("k2hash.GetContext(abandon_key) return err %v, want context.Canceled", err)
+	}
+	// 2. Close waits for the abandoned operation
+	closed := make(chan struct{})
+	go func() {
+		k.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+		t.Errorf("k2hash.Close() returns while an abandoned operation is running")
+	case <-time.After(100 * time.Millisecond):
+	}
+	close(release)
+	<-closed
+	if stats := k.ContextStats(); stats.Abandoned != 1 || stats.Undone != 0 || stats.UndoFailed != 0 {
+		t.Errorf("k2hash.ContextStats() = %v, want 1 abandoned", stats)
+	}
+}
+
+// testQueueContextAbandon tests values popped by abandoned operations are pushed back.
+// Operations are canceled while they may be running, so that some of them are abandoned.
+func testQueueContextAbandon(t *testing.T) {
+	f := "/tmp/test.k2h"
+	k, err := k2hash.NewK2hash(f, k2hash.WithRemoveFile(true))
+	if err != nil {
+		t.Errorf("k2hash.NewK2hash(%v) return err %v", f, err)
+		return
+	}
+	defer k.Close()
+	total := 100
+	want := map[string]int{}
+	for i := 0; i < total; i++ {
+		val := fmt.Sprintf("abandon_val%v", i)
+		want[val] = 1
+		if ok, err := k.Set(fmt.Sprintf("abandon_key%v", i), val); !ok {
+			t.Errorf("k2hash.Set(abandon_key%v) return false. want true. err %v", i, err)
+		}
+	}
+	// 1. Queue
+	q, err := k2hash.NewQueue(k, k2hash.WithQueuePrefix("abandon_q_"))
+	if err != nil {
+		t.Errorf("k2hash.NewQueue() return err %v", err)
+		return
+	}
+	for val := range want {
+		if ok, err := q.Push(val); !ok {
+			t.Errorf("Queue.Push(%v) return false. want true. err %v", val, err)
+		}
+	}
+	got := map[string]int{}
+	for i := 0; i < total*10; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		go cancel()
+		if val, err := q.PopContext(ctx); err == nil {
+			got[val]++
+		}
+	}
+	// Free waits for abandoned operations.
+	q.Free()
+	if q, err = k2hash.NewQueue(k, k2hash.WithQueuePrefix("abandon_q_")); err != nil {
+		t.Errorf("k2hash.NewQueue() return err %v", err)
+		return
+	}
+	for {
+		val, err := q.Pop()
+		if err != nil {
+			break
+		}
+		got[val]++
+	}
+	q.Free()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Queue.PopContext() loses or duplicates values: %v", got)
+	}
+	// 2. KeyQueue
+	kq, err := k2hash.NewKeyQueue(k, k2hash.WithKeyQueuePrefix("abandon_kq_"))
+	if err != nil {
+		t.Errorf("k2hash.NewKeyQueue() return err %v", err)
+		return
+	}
+	for i := 0; i < total; i++ {
+		if ok, err := kq.Push(fmt.Sprintf("abandon_key%v", i)); !ok {
+			t.Errorf("KeyQueue.Push(abandon_key%v) return false. want true. err %v", i, err)
+		}
+	}
+	got = map[string]int{}
+	for i := 0; i < total*10; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		go cancel()
+		if val, err := kq.PopContext(ctx); err == nil {
+			got[val]++
+		}
+	}
+	kq.Free()
+	if kq, err = k2hash.NewKeyQueue(k, k2hash.WithKeyQueuePrefix("abandon_kq_")); err != nil {
+		t.Errorf("k2hash.NewKeyQueue() return err %v", err)
+		return
+	}
+	for {
+		val, err := kq.Pop()
+		if err != nil {
+			break
+		}
+		got[val]++
+	}
+	kq.Free()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("KeyQueue.PopContext() loses or duplicates values: %v", got)
+	}
+	stats := k.ContextStats()
+	if stats.UndoFailed != 0 || stats.Undone > stats.Abandoned {
+		t.Errorf("k2hash.ContextStats() = %v, want no undo failures", stats)
+	}
+	if stats.Abandoned == 0 {
+		t.Logf("no operation is abandoned: %v", stats)
+	}
+}
+
+// testCloseContext tests k2hash.CloseContext.
+func testCloseContext(t *testing.T) {
+	f := "/tmp/test.k2h"
+	k, err := k2hash.NewK2hash(f, k2hash.WithRemoveFile(true))
+	if err != nil {
+		t.Errorf("k2hash.NewK2hash(%v) return err %v", f, err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if ok, err := k.CloseContext(ctx); !ok {
+		t.Errorf("k2hash.CloseContext() return false. want true. err %v", err)
+	}
+	if _, err := k.CloseContext(ctx); !errors.Is(err, k2hash.ErrClosed) {
+		t.Errorf("k2hash.CloseContext() return err %v, want ErrClosed", err)
+	}
+}
+
+// Local Variables:
+// c-basic-offset: 4
+// tab-width: 4
+// indent-tabs-mode: t
+// End:
+// vim600: noexpandtab sw=4 ts=4 fdm=marker
+// vim<600: noexpandtab sw=4 ts=4
@@ -0,0 +1,227 @@
+//
+// k2hash_go
+//
+// Copyright 2018 Yahoo Japan Corporation.
+//
+// Go driver for k2hash that is a NoSQL Key Value Store(KVS) library.
+// For k2hash, see https://github.com/yahoojapan/k2hash for the details.
+//
+// For the full copyright and license information, please view
+// the license file that was distributed with this source code.
+//
+// AUTHOR:   Hirotaka Wakabayashi
+// CREATE:   Sun, 18 Oct 2026
+// REVISION:
+//
+
+package k2hashtest
+
+import (
+	// #cgo CFLAGS: -g -Wall -O2 -Wall -Wextra -Wno-unused-variable -Wno-unused-parameter -I. -I/usr/include/k2hash
+	// #cgo LDFLAGS: -L/usr/lib -lk2hash
+	// #include <stdlib.h>
+	// #include "k2hash.h"
+	"C"
+)
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yahoojapan/k2hash_go/k2hash"
+)
+
+// The actual test functions are in non-_test.go files
+// so that they can use cgo (import "C").
+// These wrappers are here for gotest to find.
+
+// testGetEntry tests k2hash.GetEntry.
+func testGetEntry(t *testing.T) {
+	f := "/tmp/test.k2h"
+	k, err := k2hash.NewK2hash(f, k2hash.WithRemoveFile(true))
+	if err != nil {
+		t.Errorf("k2hash.NewK2hash(%v) return err %v", f, err)
+		return
+	}
+	defer k.Close()
+	if ok, err := k.EnableMtime(true); !ok {
+		t.Errorf("k2hash.EnableMtime(true) return false. want true. err %v", err)
+	}
+	// 1. set all parts
+	now := time.Now()
+	if ok, err := k.Set("entry_key", "entry_val", k2hash.WithExpire(time.Hour)); !ok {
+		t.Errorf("k2hash.Set(entry_key) return false. want true. err %v", err)
+	}
+	if ok, err := k.SetSubKeys("entry_key", []string{"entry_subkey1", "entry_subkey2"}); !ok {
+		t.Errorf("k2hash.SetSubKeys(entry_key) return false. want true. err %v", err)
+	}
+	if ok, err := k.AddAttr("entry_key", "entry_attr", "entry_attr_val"); !ok {
+		t.Errorf("k2hash.AddAttr(entry_key) return false. want true. err %v", err)
+	}
+	// 2. get all parts
+	e, err := k.GetEntry("entry_key")
+	if err != nil {
+		t.Errorf("k2hash.GetEntry(entry_key) return err %v", err)
+		return
+	}
+	if string(e.Value) != "entry_val\x00" {
+		t.Errorf("Entry.Value = %q, want entry_val\\x00", e.Value)
+	}
+	if len(e.SubKeys) != 2 || string(e.SubKeys[0]) != "entry_subkey1\x00" || string(e.SubKeys[1]) != "entry_subkey2\x00" {
+		t.Errorf("Entry.SubKeys = %q, want [entry_subkey1 entry_subkey2]", e.SubKeys)
+	}
+	if !entryHasAttr(e, "entry_attr_val") {
+		t.Errorf("Entry.Attrs = %v, want entry_attr", e.Attrs)
+	}
+	if e.Mtime.Before(now.Add(-time.Minute)) || e.Mtime.After(now.Add(time.Minute)) {
+		t.Errorf("Entry.Mtime = %v, want about %v", e.Mtime, now)
+	}
+	if want := now.Add(time.Hour); e.Expire.Before(want.Add(-time.Minute)) || e.Expire.After(want.Add(time.Minute)) {
+		t.Errorf("Entry.Expire = %v, want about %v", e.Expire, want)
+	}
+	// 3. a key without subkeys and attributes
+	if ok, err := k.Set([]byte("entry_bin"), []byte{0x00, 0x01}); !ok {
+		t.Errorf("k2hash.Set(entry_bin) return false. want true. err %v", err)
+	}
+	if e, err := k.GetEntry([]byte("entry_bin")); err != nil || len(e.Value) != 2 || len(e.SubKeys) != 0 || !e.Expire.IsZero() {
+		t.Errorf("k2hash.GetEntry(entry_bin) = (%v, %v), want a value without subkeys", e, err)
+	}
+	// 4. no such key
+	if _, err := k.GetEntry("entry_nokey"); !errors.Is(err, k2hash.ErrNotFound) {
+		t.Errorf("k2hash.GetEntry(entry_nokey) return err %v, want ErrNotFound", err)
+	}
+}
+
+// testSetEntry tests k2hash.SetEntry.
+func testSetEntry(t *testing.T) {
+	f := "/tmp/test.k2h"
+	k, err := k2hash.NewK2hash(f, k2hash.WithRemoveFile(true))
+	if err != nil {
+		t.Errorf("k2hash.NewK2hash(%v) return err %v", f, err)
+		return
+	}
+	defer k.Close()
+	// 1. set all parts
+	expire := time.Now().Add(time.Hour)
+	e := k2hash.Entry{
+		Value:   []byte("entry_val"),
+		SubKeys: [][]byte{[]byte("entry_subkey1"), []byte("entry_subkey2")},
+		Attrs:   []k2hash.Attr{k2hash.NewAttr("entry_attr1", []byte("entry_attr_val1"))},
+		Expire:  expire,
+	}
+	if ok, err := k.SetEntry("entry_key", e); !ok {
+		t.Errorf("k2hash.SetEntry(entry_key) return false. want true. err %v", err)
+		return
+	}
+	got, err := k.GetEntry("entry_key")
+	if err != nil {
+		t.Errorf("k2hash.GetEntry(entry_key) return err %v", err)
+		return
+	}
+	if string(got.Value) != "entry_val" || len(got.SubKeys) != 2 || !entryHasAttr(got, "entry_attr_val1") {
+		t.Errorf("k2hash.GetEntry(entry_key) = %v, want %v", got, e)
+	}
+	if got.Expire.Before(expire.Add(-time.Minute)) || got.Expire.After(expire.Add(time.Minute)) {
+		t.Errorf("Entry.Expire = %v, want about %v", got.Expire, expire)
+	}
+	// 2. keep the existing attributes
+	e = k2hash.Entry{
+		Value: []byte("entry_val2"),
+		Attrs: []k2hash.Attr{k2hash.NewAttr("entry_attr2", []byte("entry_attr_val2"))},
+	}
+	if ok, err := k.SetEntry("entry_key", e); !ok {
+		t.Errorf("k2hash.SetEntry(entry_key) return false. want true. err %v", err)
+	}
+	got, err = k.GetEntry("entry_key")
+	if err != nil || string(got.Value) != "entry_val2" || len(got.SubKeys) != 0 ||
+		!entryHasAttr(got, "entry_attr_val1") || !entryHasAttr(got, "entry_attr_val2") {
+		t.Errorf("k2hash.GetEntry(entry_key) = (%v, %v), want entry_attr1 and entry_attr2", got, err)
+	}
+	// 3. replace the existing attribute with the same name
+	e = k2hash.Entry{
+		Value: []byte("entry_val3"),
+		Attrs: []k2hash.Attr{k2hash.NewAttr("entry_attr2", []byte("entry_attr_val3"))},
+	}
+	if ok, err := k.SetEntry("entry_key", e); !ok {
+		t.Errorf("k2hash.SetEntry(entry_key) return false. want true. err %v", err)
+	}
+	got, err = k.GetEntry("entry_key")
+	if err != nil || !entryHasAttr(got, "entry_attr_val1") || entryHasAttr(got, "entry_attr_val2") ||
+		!entryHasAttr(got, "entry_attr_val3") {
+		t.Errorf("k2hash.GetEntry(entry_key) = (%v, %v), want entry_attr1 and the new entry_attr2", got, err)
+		return
+	}
+	// 4. write back an entry with the builtin attributes
+	if ok, err := k.SetEntry("entry_key", *got); !ok {
+		t.Errorf("k2hash.SetEntry(entry_key) with the builtin attributes return false. want true. err %v", err)
+	}
+	again, err := k.GetEntry("entry_key")
+	if err != nil || len(again.Attrs) != len(got.Attrs) {
+		t.Errorf("k2hash.GetEntry(entry_key) = (%v, %v), want the attributes %v", again, err, got.Attrs)
+	}
+	// 5. replace the existing attributes
+	e = k2hash.Entry{
+		Value: []byte("entry_val4"),
+		Attrs: []k2hash.Attr{k2hash.NewAttr("entry_attr3", []byte("entry_attr_val4"))},
+	}
+	if ok, err := k.SetEntry("entry_key", e, k2hash.WithReplaceAttrs()); !ok {
+		t.Errorf("k2hash.SetEntry(entry_key, WithReplaceAttrs) return false. want true. err %v", err)
+	}
+	got, err = k.GetEntry("entry_key")
+	if err != nil || entryHasAttr(got, "entry_attr_val1") || entryHasAttr(got, "entry_attr_val3") ||
+		!entryHasAttr(got, "entry_attr_val4") {
+		t.Errorf("k2hash.GetEntry(entry_key) = (%v, %v), want entry_attr3 only", got, err)
+	}
+	// 6. a staging key left by a crash is hidden, and removed by the next write
+	staging := []byte("\x00k2hash_go:staging:entry_key\x00")
+	if ok, err := k.Set(staging, "entry_partial"); !ok {
+		t.Errorf("k2hash.Set(%q) return false. want true. err %v", staging, err)
+		return
+	}
+	n := 0
+	if err := k.Iterate(func(key, val []byte) error {
+		if bytes.Equal(key, staging) {
+			t.Errorf("k2hash.Iterate() returns the staging key %q", key)
+		}
+		n++
+		return nil
+	}); err != nil || n != 1 {
+		t.Errorf("k2hash.Iterate() walks %v keys with err %v, want 1 key", n, err)
+	}
+	if ok, err := k.SetEntry("entry_key", e); !ok {
+		t.Errorf("k2hash.SetEntry(entry_key) return false. want true. err %v", err)
+	}
+	if _, err := k.Get(staging); !errors.Is(err, k2hash.ErrNotFound) {
+		t.Errorf("k2hash.Get(%q) return err %v, want ErrNotFound", staging, err)
+	}
+	// 7. invalid expiration
+	e.Expire = time.Now().Add(-time.Hour)
+	if _, err := k.SetEntry("entry_key", e); !errors.Is(err, k2hash.ErrInvalid) {
+		t.Errorf("k2hash.SetEntry(entry_key) with a past expiration return err %v, want ErrInvalid", err)
+	}
+	got, err = k.GetEntry("entry_key")
+	if err != nil || string(got.Value) != "entry_val4" {
+		t.Errorf("k2hash.GetEntry(entry_key) = (%v, %v), want the entry left as it is", got, err)
+	}
+}
+
+// entryHasAttr returns true if the entry has an attribute which contains s.
+func entryHasAttr(e *k2hash.Entry, s string) bool {
+	for _, a := range e.Attrs {
+		if strings.Contains(a.String(), s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Local Variables:
+// c-basic-offset: 4
+// tab-width: 4
+// indent-tabs-mode: t
+// End:
+// vim600: noexpandtab sw=4 ts=4 fdm=marker
+// vim<600: noexpandtab sw=4 ts=4
@@ -0,0 +1,110 @@
+//
+// k2hash_go
+//
+// Copyright 2018 Yahoo Japan Corporation.
+//
+// Go driver for k2hash that is a NoSQL Key Value Store(KVS) library.
+// For k2hash, see https://github.com/yahoojapan/k2hash for the details.
+//
+// For the full copyright and license information, please view
+// the license file that was distributed with this source code.
+//
+// AUTHOR:   Hirotaka Wakabayashi
+// CREATE:   Sun, 18 Oct 2026
+// REVISION:
+//
+
+package k2hashtest
+
+import (
+	// #cgo CFLAGS: -g -Wall -O2 -Wall -Wextra -Wno-unused-variable -Wno-unused-parameter -I. -I/usr/include/k2hash
+	// #cgo LDFLAGS: -L/usr/lib -lk2hash
+	// #include <stdlib.h>
+	// #include "k2hash.h"
+	"C"
+)
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/yahoojapan/k2hash_go/k2hash"
+)
+
+// The actual test functions are in non-_test.go files
+// so that they can use cgo (import "C").
+// These wrappers are here for gotest to find.
+
+// testErrors tests the sentinel errors and OpError.
+func testErrors(t *testing.T) {
+	if ok, err := clearIfExists("/tmp/test.k2h", "errors_key"); !ok {
+		t.Errorf("clearIfExists(%v, %v) = (%v, %v)", "/tmp/test.k2h", "errors_key", ok, err)
+	}
+	k, err := k2hash.NewK2hash("/tmp/test.k2h")
+	if err != nil {
+		t.Errorf("k2hash.NewK2hash(/tmp/test.k2h) return err %v", err)
+		return
+	}
+	// 1. not found
+	_, err = k.Get("errors_key")
+	if !errors.Is(err, k2hash.ErrNotFound) {
+		t.Errorf("k2hash.Get(errors_key) return err %v, want ErrNotFound", err)
+	}
+	var opErr *k2hash.OpError
+	if !errors.As(err, &opErr) || opErr.Op != "Get" || string(opErr.Key) != "errors_key\x00" {
+		t.Errorf("k2hash.Get(errors_key) return err %#v, want OpError", err)
+	}
+	if want := `k2hash: Get "errors_key": not found`; err == nil || err.Error() != want {
+		t.Errorf("k2hash.Get(errors_key) return err %v, want %v", err, want)
+	}
+	if ok, err := k.Rename("errors_key", "errors_newkey"); ok || !errors.Is(err, k2hash.ErrNotFound) {
+		t.Errorf("k2hash.Rename(errors_key) = (%v, %v), want ErrNotFound", ok, err)
+	}
+	// 2. invalid argument
+	if ok, err := k.Set(1, "val"); ok || !errors.Is(err, k2hash.ErrInvalid) {
+		t.Errorf("k2hash.Set(1) = (%v, %v), want ErrInvalid", ok, err)
+	}
+	// 3. decryption
+	if ok, err := k.Set("errors_key", "val", k2hash.WithPassword("pass")); !ok {
+		t.Errorf("k2hash.Set(WithPassword(pass)) return false. want true. err %v", err)
+	}
+	if _, err := k.Get("errors_key", k2hash.WithPassword("wrong")); !errors.Is(err, k2hash.ErrDecrypt) {
+		t.Errorf("k2hash.Get(WithPassword(wrong)) return err %v, want ErrDecrypt", err)
+	}
+	// 4. empty queue
+	q, err := k2hash.NewQueue(k, k2hash.WithQueuePrefix("errors_queue_"))
+	if err != nil {
+		t.Errorf("k2hash.NewQueue() return err %v", err)
+	} else {
+		if _, err := q.Pop(); !errors.Is(err, k2hash.ErrQueueEmpty) {
+			t.Errorf("Queue.Pop() return err %v, want ErrQueueEmpty", err)
+		}
+		q.Free()
+		if _, err := q.Pop(); !errors.Is(err, k2hash.ErrClosed) {
+			t.Errorf("Queue.Pop() after Free return err %v, want ErrClosed", err)
+		}
+	}
+	// 5. closed
+	k.Close()
+	if _, err := k.Get("errors_key"); !errors.Is(err, k2hash.ErrClosed) {
+		t.Errorf("k2hash.Get() after Close return err %v, want ErrClosed", err)
+	}
+	// 6. read only
+	r, err := k2hash.NewK2hash("/tmp/test.k2h", k2hash.WithReadOnly(true))
+	if err != nil {
+		t.Errorf("k2hash.NewK2hash(WithReadOnly(true)) return err %v", err)
+		return
+	}
+	defer r.Close()
+	if ok, err := r.Remove("errors_key"); ok || !errors.Is(err, k2hash.ErrReadOnly) {
+		t.Errorf("k2hash.Remove() on a read only file = (%v, %v), want ErrReadOnly", ok, err)
+	}
+}
+
+// Local Variables:
+// c-basic-offset: 4
+// tab-width: 4
+// indent-tabs-mode: t
+// End:
+// vim600: noexpandtab sw=4 ts=4 fdm=marker
+// vim<600: noexpandtab sw=4 ts=4
@@ -0,0 +1,191 @@
+//
+// k2hash_go
+//
+// Copyright 2018 Yahoo Japan Corporation.
+//
+// Go driver for k2hash that is a NoSQL Key Value Store(KVS) library.
+// For k2hash, see https://github.com/yahoojapan/k2hash for the details.
+//
+// For the full copyright and license information, please view
+// the license file that was distributed with this source code.
+//
+// AUTHOR:   Hirotaka Wakabayashi
+// CREATE:   Sun, 18 Oct 2026
+// REVISION:
+//
+
+package k2hashtest
+
+import (
+	// #cgo CFLAGS: -g -Wall -O2 -Wall -Wextra -Wno-unused-variable -Wno-unused-parameter -I. -I/usr/include/k2hash
+	// #cgo LDFLAGS: -L/usr/lib -lk2hash
+	// #include <stdlib.h>
+	// #include "k2hash.h"
+	"C"
+)
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/yahoojapan/k2hash_go/k2hash"
+)
+
+// The actual test functions are in non-_test.go files
+// so that they can use cgo (import "C").
+// These wrappers are here for gotest to find.
+
+// testGetInto tests k2hash.GetInto.
+func testGetInto(t *testing.T) {
+	f := "/tmp/test.k2h"
+	k, err := k2hash.NewK2hash(f, k2hash.WithRemoveFile(true))
+	if err != nil {
+		t.Errorf("k2hash.NewK2hash(%v) return err %v", f, err)
+		return
+	}
+	defer k.Close()
+	key := []byte("getinto_key")
+	val := []byte{0x00, 0x01, 0x02}
+	if ok, err := k.Set(key, val); !ok {
+		t.Errorf("k2hash.Set(%v) return false. want true. err %v", key, err)
+		return
+	}
+	// 1. append to a buffer
+	buf := make([]byte, 0, 64)
+	buf = append(buf, "prefix"...)
+	got, err := k.GetInto(key, buf)
+	if err != nil || !bytes.Equal(got, append([]byte("prefix"), val...)) {
+		t.Errorf("k2hash.GetInto(%v) = (%v, %v), want prefix + %v", key, got, err, val)
+	}
+	if &got[0] != &buf[:1][0] {
+		t.Errorf("k2hash.GetInto(%v) allocates a new buffer, want to reuse dst", key)
+	}
+	// 2. a string value contains the null termination
+	if ok, err := k.Set("getinto_str", "value"); !ok {
+		t.Errorf("k2hash.Set(getinto_str) return false. want true. err %v", err)
+	}
+	if got, err := k.GetInto("getinto_str", nil); err != nil || string(got) != "value\x00" {
+		t.Errorf("k2hash.GetInto(getinto_str) = (%q, %v), want value\\x00", got, err)
+	}
+	// 3. no such key
+	if got, err := k.GetInto("getinto_nokey", buf[:0]); !errors.Is(err, k2hash.ErrNotFound) || len(got) != 0 {
+		t.Errorf("k2hash.GetInto(getinto_nokey) = (%v, %v), want ErrNotFound", got, err)
+	}
+}
+
+// testBufferPool tests k2hash.WithBufferPool.
+func testBufferPool(t *testing.T) {
+	k, err := k2hash.NewMemoryK2hash(k2hash.WithBufferPool(k2hash.NewBufferPool(64)))
+	if err != nil {
+		t.Errorf("k2hash.NewMemoryK2hash() return err %v", err)
+		return
+	}
+	defer k.Close()
+	for _, val := range []string{"pool_val1", "pool_val2_longer"} {
+		if ok, err := k.Set("pool_key", val); !ok {
+			t.Errorf("k2hash.Set(pool_key) return false. want true. err %v", err)
+		}
+		r, err := k.Get("pool_key")
+		if err != nil || r.String() != val {
+			t.Errorf("k2hash.Get(pool_key) = (%v, %v), want %v", r, err, val)
+			continue
+		}
+		r.Release()
+		if r.Bytes() != nil {
+			t.Errorf("GetResult.Bytes() after Release = %v, want nil", r.Bytes())
+		}
+		r.Release()
+	}
+	// Release doesn't allocate, so that a pooled Get allocates less than a Get without a pool.
+	key := []byte("pool_key")
+	if ok, err := k.Set(key, bytes.Repeat([]byte("v"), 1024)); !ok {
+		t.Errorf("k2hash.Set(pool_key) return false. want true. err %v", err)
+	}
+	u, err := k2hash.NewMemoryK2hash()
+	if err != nil {
+		t.Errorf("k2hash.NewMemoryK2hash() return err %v", err)
+		return
+	}
+	defer u.Close()
+	if ok, err := u.Set(key, bytes.Repeat([]byte("v"), 1024)); !ok {
+		t.Errorf("k2hash.Set(pool_key) return false. want true. err %v", err)
+	}
+	pooled := testing.AllocsPerRun(100, func() {
+		if r, err := k.Get(key); err == nil {
+			r.Release()
+		}
+	})
+	unpooled := testing.AllocsPerRun(100, func() {
+		u.Get(key)
+	})
+	if pooled >= unpooled {
+		t.Errorf("a pooled Get allocates %v times, want less than %v", pooled, unpooled)
+	}
+}
+
+// benchmarkGetValue gets a value by k2hash.Get.
+func benchmarkGetValue(b *testing.B) {
+	k := benchK2hash(b)
+	defer k.Close()
+	key := []byte("bench_key")
+	if ok, err := k.Set(key, bytes.Repeat([]byte("v"), 1024)); !ok {
+		b.Fatalf("k2hash.Set(%v) return err %v", key, err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		if _, err := k.Get(key); err != nil {
+			b.Fatalf("k2hash.Get(%v) return err %v", key, err)
+		}
+	}
+}
+
+// benchmarkGetInto gets a value by k2hash.GetInto with a reused buffer.
+func benchmarkGetInto(b *testing.B) {
+	k := benchK2hash(b)
+	defer k.Close()
+	key := []byte("bench_key")
+	if ok, err := k.Set(key, bytes.Repeat([]byte("v"), 1024)); !ok {
+		b.Fatalf("k2hash.Set(%v) return err %v", key, err)
+	}
+	buf := make([]byte, 0, 1024)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		var err error
+		if buf, err = k.GetInto(key, buf[:0]); err != nil {
+			b.Fatalf("k2hash.GetInto(%v) return err %v", key, err)
+		}
+	}
+}
+
+// benchmarkGetPooled gets a value by k2hash.Get with a buffer pool.
+func benchmarkGetPooled(b *testing.B) {
+	k, err := k2hash.NewMemoryK2hash(k2hash.WithBufferPool(k2hash.NewBufferPool(1024)))
+	if err != nil {
+		b.Fatalf("k2hash.NewMemoryK2hash() return err %v", err)
+	}
+	defer k.Close()
+	key := []byte("bench_key")
+	if ok, err := k.Set(key, bytes.Repeat([]byte("v"), 1024)); !ok {
+		b.Fatalf("k2hash.Set(%v) return err %v", key, err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		r, err := k.Get(key)
+		if err != nil {
+			b.Fatalf("k2hash.Get(%v) return err %v", key, err)
+		}
+		r.Release()
+	}
+}
+
+// Local Variables:
+// c-basic-offset: 4
+// tab-width: 4
+// indent-tabs-mode: t
+// End:
+// vim600: noexpandtab sw=4 ts=4 fdm=marker
+// vim<600: noexpandtab sw=4 ts=4
@@ -25,6 +25,8 @@ import (
 )
 
 import (
+	"errors"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -103,6 +105,90 @@ func testGetSubKeysArgs(key string, skeys []string, t *testing.T) {
 	}
 }
 
+// testGetSubKeysEmpty tests k2hash.GetSubKeys returns no empty subkey.
+func testGetSubKeysEmpty(t *testing.T) {
+	f := "/tmp/test.k2h"
+	k, err := k2hash.NewK2hash(f, k2hash.WithRemoveFile(true))
+	if err != nil {
+		t.Errorf("k2hash.NewK2hash(%v) return err %v", f, err)
+		return
+	}
+	defer k.Close()
+	// 1. no subkeys
+	if ok, err := k.Set("getsubkeys_empty", "val"); !ok {
+		t.Errorf("k2hash.Set(getsubkeys_empty) return false. want true. err %v", err)
+	}
+	if skeys, err := k.GetSubKeys("getsubkeys_empty"); err != nil || skeys == nil || len(skeys) != 0 {
+		t.Errorf("k2hash.GetSubKeys(getsubkeys_empty) = (%q, %v), want []", skeys, err)
+	}
+	// 2. no key
+	if skeys, err := k.GetSubKeys("getsubkeys_none"); !errors.Is(err, k2hash.ErrNotFound) || skeys != nil {
+		t.Errorf("k2hash.GetSubKeys(getsubkeys_none) = (%q, %v), want (nil, ErrNotFound)", skeys, err)
+	}
+}
+
+// testSubKeys tests k2hash.SubKeys.
+func testSubKeys(t *testing.T) {
+	f := "/tmp/test.k2h"
+	k, err := k2hash.NewK2hash(f, k2hash.WithRemoveFile(true))
+	if err != nil {
+		t.Errorf("k2hash.NewK2hash(%v) return err %v", f, err)
+		return
+	}
+	defer k.Close()
+	// 1. subkeys with values and an attribute, and a subkey which doesn't exist
+	if ok, err := k.Set("subkeys_parent", "parent_val"); !ok {
+		t.Errorf("k2hash.Set(subkeys_parent) return false. want true. err %v", err)
+	}
+	for _, key := range []string{"subkeys_child1", "subkeys_child2"} {
+		if ok, err := k.Set(key, key+"_val"); !ok {
+			t.Errorf("k2hash.Set(%v) return false. want true. err %v", key, err)
+		}
+	}
+	if ok, err := k.AddAttr("subkeys_child2", "child_attr", "child_attr_val"); !ok {
+		t.Errorf("k2hash.AddAttr(subkeys_child2) return false. want true. err %v", err)
+	}
+	if ok, err := k.SetSubKeys("subkeys_parent", []string{"subkeys_child1", "subkeys_none", "subkeys_child2"}); !ok {
+		t.Errorf("k2hash.SetSubKeys(subkeys_parent) return false. want true. err %v", err)
+	}
+	// 2. iterate
+	var keys []string
+	for sk, err := range k.SubKeys("subkeys_parent") {
+		name := strings.TrimSuffix(string(sk.Key), "\x00")
+		keys = append(keys, name)
+		switch name {
+		case "subkeys_none":
+			if !errors.Is(err, k2hash.ErrNotFound) {
+				t.Errorf("k2hash.SubKeys() yields err %v for %v, want ErrNotFound", err, name)
+			}
+		default:
+			if err != nil || string(sk.Value) != name+"_val\x00" {
+				t.Errorf("k2hash.SubKeys() yields (%v, %v), want %v_val", sk, err, name)
+			}
+		}
+		if val, ok := sk.Attrs.Get("child_attr"); name == "subkeys_child2" && (!ok || string(val) != "child_attr_val\x00") {
+			t.Errorf("k2hash.SubKeys() yields attrs %v for %v, want child_attr", sk.Attrs, name)
+		}
+	}
+	if want := []string{"subkeys_child1", "subkeys_none", "subkeys_child2"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("k2hash.SubKeys() yields %v, want %v", keys, want)
+	}
+	// 3. break and errors
+	count := 0
+	for range k.SubKeys("subkeys_parent") {
+		count++
+		break
+	}
+	if count != 1 {
+		t.Errorf("k2hash.SubKeys() yields %v subkeys before break, want 1", count)
+	}
+	for _, err := range k.SubKeys("subkeys_nokey") {
+		if !errors.Is(err, k2hash.ErrNotFound) {
+			t.Errorf("k2hash.SubKeys(subkeys_nokey) yields err %v, want ErrNotFound", err)
+		}
+	}
+}
+
 // Local Variables:
 // c-basic-offset: 4
 // tab-width: 4
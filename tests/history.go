@@ -0,0 +1,108 @@
+//
+// k2hash_go
+//
+// Copyright 2018 Yahoo Japan Corporation.
+//
+// Go driver for k2hash that is a NoSQL Key Value Store(KVS) library.
+// For k2hash, see https://github.com/yahoojapan/k2hash for the details.
+//
+// For the full copyright and license information, please view
+// the license file that was distributed with this source code.
+//
+// AUTHOR:   Hirotaka Wakabayashi
+// CREATE:   Sun, 18 Oct 2026
+// REVISION:
+//
+
+package k2hashtest
+
+import (
+	// #cgo CFLAGS: -g -Wall -O2 -Wall -Wextra -Wno-unused-variable -Wno-unused-parameter -I. -I/usr/include/k2hash
+	// #cgo LDFLAGS: -L/usr/lib -lk2hash
+	// #include <stdlib.h>
+	// #include "k2hash.h"
+	"C"
+)
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yahoojapan/k2hash_go/k2hash"
+)
+
+// The actual test functions are in non-_test.go files
+// so that they can use cgo (import "C").
+// These wrappers are here for gotest to find.
+
+// testHistory tests k2hash.History and k2hash.GetAsOf.
+func testHistory(t *testing.T) {
+	f := "/tmp/test.k2h"
+	k, err := k2hash.NewK2hash(f, k2hash.WithRemoveFile(true))
+	if err != nil {
+		t.Errorf("k2hash.NewK2hash(%v) return err %v", f, err)
+		return
+	}
+	defer k.Close()
+	if ok, err := k.EnableMtime(true); !ok {
+		t.Errorf("k2hash.EnableMtime(true) return false. want true. err %v", err)
+	}
+	if ok, err := k.EnableHistory(true); !ok {
+		t.Errorf("k2hash.EnableHistory(true) return false. want true. err %v", err)
+	}
+	// 1. set versions. mtime may be saved in seconds.
+	vals := []string{"history_val1", "history_val2", "history_val3"}
+	times := make([]time.Time, len(vals))
+	before := time.Now().Add(-time.Second)
+	for i, val := range vals {
+		if i > 0 {
+			time.Sleep(1100 * time.Millisecond)
+		}
+		if ok, err := k.Set("history_key", val); !ok {
+			t.Errorf("k2hash.Set(history_key, %v) return false. want true. err %v", val, err)
+		}
+		times[i] = time.Now()
+	}
+	// 2. History
+	versions, err := k.History("history_key")
+	if err != nil || len(versions) != 2 {
+		t.Errorf("k2hash.History(history_key) = (%v, %v), want 2 versions", versions, err)
+		return
+	}
+	for i, v := range versions {
+		if want := vals[len(vals)-2-i] + "\x00"; string(v.Value) != want || v.UUID == "" || v.Mtime.IsZero() {
+			t.Errorf("k2hash.History(history_key)[%v] = %v, want %q", i, v, want)
+		}
+	}
+	// 3. GetAsOf
+	for i, at := range times {
+		if val, err := k.GetAsOf("history_key", at); err != nil || val.String() != vals[i] {
+			t.Errorf("k2hash.GetAsOf(history_key, %v) = (%v, %v), want %v", at, val, err, vals[i])
+		}
+	}
+	if _, err := k.GetAsOf("history_key", before); !errors.Is(err, k2hash.ErrNotFound) {
+		t.Errorf("k2hash.GetAsOf(history_key, %v) return err %v, want ErrNotFound", before, err)
+	}
+	// 4. no history
+	if ok, err := k.EnableHistory(false); !ok {
+		t.Errorf("k2hash.EnableHistory(false) return false. want true. err %v", err)
+	}
+	if ok, err := k.Set("history_none", "history_val"); !ok {
+		t.Errorf("k2hash.Set(history_none) return false. want true. err %v", err)
+	}
+	if versions, err := k.History("history_none"); err != nil || len(versions) != 0 {
+		t.Errorf("k2hash.History(history_none) = (%v, %v), want no versions", versions, err)
+	}
+	if _, err := k.History("history_nokey"); !errors.Is(err, k2hash.ErrNotFound) {
+		t.Errorf("k2hash.History(history_nokey) return err %v, want ErrNotFound", err)
+	}
+}
+
+// Local Variables:
+// c-basic-offset: 4
+// tab-width: 4
+// indent-tabs-mode: t
+// End:
+// vim600: noexpandtab sw=4 ts=4 fdm=marker
+// vim<600: noexpandtab sw=4 ts=4
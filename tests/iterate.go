@@ -0,0 +1,114 @@
+//
+// k2hash_go
+//
+// Copyright 2018 Yahoo Japan Corporation.
+//
+// Go driver for k2hash that is a NoSQL Key Value Store(KVS) library.
+// For k2hash, see https://github.com/yahoojapan/k2hash for the details.
+//
+// For the full copyright and license information, please view
+// the license file that was distributed with this source code.
+//
+// AUTHOR:   Hirotaka Wakabayashi
+// CREATE:   Sun, 18 Oct 2026
+// REVISION:
+//
+
+package k2hashtest
+
+import (
+	// #cgo CFLAGS: -g -Wall -O2 -Wall -Wextra -Wno-unused-variable -Wno-unused-parameter -I. -I/usr/include/k2hash
+	// #cgo LDFLAGS: -L/usr/lib -lk2hash
+	// #include <stdlib.h>
+	// #include "k2hash.h"
+	"C"
+)
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/yahoojapan/k2hash_go/k2hash"
+)
+
+// The actual test functions are in non-_test.go files
+// so that they can use cgo (import "C").
+// These wrappers are here for gotest to find.
+
+// testIterate tests k2hash.Iterate, k2hash.Keys and k2hash.Cursor.
+func testIterate(t *testing.T) {
+	// 1. define test data.
+	testData := map[string]string{
+		"iterate_key1": "iterate_val1",
+		"iterate_key2": "iterate_val2",
+		"iterate_key3": "iterate_val3",
+	}
+	k, err := k2hash.NewK2hash("/tmp/iterate.k2h", k2hash.WithRemoveFile(true))
+	if err != nil {
+		t.Errorf("k2hash.NewK2hash(/tmp/iterate.k2h) return err %v", err)
+		return
+	}
+	defer k.Close()
+	for key, val := range testData {
+		if ok, err := k.Set([]byte(key), []byte(val)); !ok {
+			t.Errorf("k2hash.Set(%v, %v) return false. want true. err %v", key, val, err)
+		}
+	}
+	// 2. Iterate
+	found := map[string]string{}
+	err = k.Iterate(func(key, val []byte) error {
+		found[string(key)] = string(val)
+		return nil
+	})
+	if err != nil {
+		t.Errorf("k2hash.Iterate() return err %v", err)
+	}
+	for key, val := range testData {
+		if found[key] != val {
+			t.Errorf("k2hash.Iterate() found %v = %v, want %v", key, found[key], val)
+		}
+	}
+	// 3. Iterate stops with an error
+	stop := errors.New("stop")
+	count := 0
+	err = k.Iterate(func(key, val []byte) error {
+		count++
+		return stop
+	})
+	if err != stop || count != 1 {
+		t.Errorf("k2hash.Iterate() = (%v, %v), want (%v, 1)", err, count, stop)
+	}
+	// 4. Keys with break
+	count = 0
+	for range k.Keys() {
+		count++
+		break
+	}
+	if count != 1 {
+		t.Errorf("k2hash.Keys() yields %v keys before break, want 1", count)
+	}
+	// 5. Cursor
+	c, err := k.NewCursor()
+	if err != nil {
+		t.Errorf("k2hash.NewCursor() return err %v", err)
+		return
+	}
+	count = 0
+	for c.Next() {
+		count++
+	}
+	if err := c.Err(); err != nil || count != len(testData) {
+		t.Errorf("Cursor.Next() walks %v keys with err %v, want %v", count, err, len(testData))
+	}
+	if err := c.Close(); err != nil {
+		t.Errorf("Cursor.Close() return err %v", err)
+	}
+}
+
+// Local Variables:
+// c-basic-offset: 4
+// tab-width: 4
+// indent-tabs-mode: t
+// End:
+// vim600: noexpandtab sw=4 ts=4 fdm=marker
+// vim<600: noexpandtab sw=4 ts=4
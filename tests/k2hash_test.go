@@ -81,6 +81,86 @@ func TestGet(t *testing.T)       { testGet(t) }
 func TestRemove(t *testing.T)    { testRemove(t) }
 func TestAddSubKey(t *testing.T) { testAddSubKey(t) }
 
+func TestSetGetBinary(t *testing.T) { testSetGetBinary(t) }
+func TestQueueBinary(t *testing.T)  { testQueueBinary(t) }
+
+func TestK2hashOptions(t *testing.T) { testK2hashOptions(t) }
+func TestParamsOptions(t *testing.T) { testParamsOptions(t) }
+func TestQueueOptions(t *testing.T)  { testQueueOptions(t) }
+
+func TestErrors(t *testing.T) { testErrors(t) }
+
+func TestIterate(t *testing.T) { testIterate(t) }
+func TestScan(t *testing.T)    { testScan(t) }
+
+func TestScanCursor(t *testing.T) { testScanCursor(t) }
+
+func TestNewMemoryK2hash(t *testing.T) { testNewMemoryK2hash(t) }
+func TestNewTempK2hash(t *testing.T)   { testNewTempK2hash(t) }
+
+func TestOpenReader(t *testing.T) { testOpenReader(t) }
+func TestOpenWriter(t *testing.T) { testOpenWriter(t) }
+
+func TestContext(t *testing.T)             { testContext(t) }
+func TestQueueContext(t *testing.T)        { testQueueContext(t) }
+func TestCloseContext(t *testing.T)        { testCloseContext(t) }
+func TestContextAbandon(t *testing.T)      { testContextAbandon(t) }
+func TestQueueContextAbandon(t *testing.T) { testQueueContextAbandon(t) }
+
+func TestStore(t *testing.T)     { testStore(t) }
+func TestStorePool(t *testing.T) { testStorePool(t) }
+
+func TestBatch(t *testing.T) { testBatch(t) }
+
+func BenchmarkSet(b *testing.B)        { benchmarkSet(b) }
+func BenchmarkSetMany(b *testing.B)    { benchmarkSetMany(b) }
+func BenchmarkGet(b *testing.B)        { benchmarkGet(b) }
+func BenchmarkGetMany(b *testing.B)    { benchmarkGetMany(b) }
+func BenchmarkRemove(b *testing.B)     { benchmarkRemove(b) }
+func BenchmarkRemoveMany(b *testing.B) { benchmarkRemoveMany(b) }
+
+func TestGetInto(t *testing.T)    { testGetInto(t) }
+func TestBufferPool(t *testing.T) { testBufferPool(t) }
+
+func BenchmarkGetValue(b *testing.B)  { benchmarkGetValue(b) }
+func BenchmarkGetInto(b *testing.B)   { benchmarkGetInto(b) }
+func BenchmarkGetPooled(b *testing.B) { benchmarkGetPooled(b) }
+
+func TestGetEntry(t *testing.T) { testGetEntry(t) }
+func TestSetEntry(t *testing.T) { testSetEntry(t) }
+
+func TestAttrs(t *testing.T) { testAttrs(t) }
+
+func TestGetAttr(t *testing.T)    { testGetAttr(t) }
+func TestSetAttr(t *testing.T)    { testSetAttr(t) }
+func TestRemoveAttr(t *testing.T) { testRemoveAttr(t) }
+
+func TestTTL(t *testing.T) { testTTL(t) }
+
+func TestSweep(t *testing.T)         { testSweep(t) }
+func TestSweepExtended(t *testing.T) { testSweepExtended(t) }
+func TestSweepBatch(t *testing.T)    { testSweepBatch(t) }
+func TestSweeper(t *testing.T)       { testSweeper(t) }
+
+func TestHistory(t *testing.T) { testHistory(t) }
+
+func TestRotateEncryption(t *testing.T) { testRotateEncryption(t) }
+
+func TestKeyProvider(t *testing.T)      { testKeyProvider(t) }
+func TestKeyProviderReads(t *testing.T) { testKeyProviderReads(t) }
+
+func TestEnvKeyProvider(t *testing.T) { testEnvKeyProvider(t) }
+
+func TestWalkSubKeys(t *testing.T) { testWalkSubKeys(t) }
+
+func TestCopyTree(t *testing.T) { testCopyTree(t) }
+
+func TestMoveTree(t *testing.T) { testMoveTree(t) }
+
+func TestGetSubKeysEmpty(t *testing.T) { testGetSubKeysEmpty(t) }
+
+func TestSubKeys(t *testing.T) { testSubKeys(t) }
+
 func TestEnableMtime(t *testing.T)           { testEnableMtime(t) }
 func TestEnableEncryption(t *testing.T)      { testEnableEncryption(t) }
 func TestEnableHistory(t *testing.T)         { testEnableHistory(t) }
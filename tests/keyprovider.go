@@ -0,0 +1,203 @@
+//
+// k2hash_go
+//
+// Copyright 2018 Yahoo Japan Corporation.
+//
+// Go driver for k2hash that is a NoSQL Key Value Store(KVS) library.
+// For k2hash, see https://github.com/yahoojapan/k2hash for the details.
+//
+// For the full copyright and license information, please view
+// the license file that was distributed with this source code.
+//
+// AUTHOR:   Hirotaka Wakabayashi
+// CREATE:   Sun, 18 Oct 2026
+// REVISION:
+//
+
+package k2hashtest
+
+import (
+	// #cgo CFLAGS: -g -Wall -O2 -Wall -Wextra -Wno-unused-variable -Wno-unused-parameter -I. -I/usr/include/k2hash
+	// #cgo LDFLAGS: -L/usr/lib -lk2hash
+	// #include <stdlib.h>
+	// #include "k2hash.h"
+	"C"
+)
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/yahoojapan/k2hash_go/k2hash"
+)
+
+// The actual test functions are in non-_test.go files
+// so that they can use cgo (import "C").
+// These wrappers are here for gotest to find.
+
+// testKeyProvider tests k2hash.WithKeyProvider and k2hash.ReloadKeys.
+func testKeyProvider(t *testing.T) {
+	f := "/tmp/test.k2h"
+	passes := []string{"kp_pass"}
+	calls := 0
+	provider := k2hash.KeyProviderFunc(func(ctx context.Context) ([][]byte, error) {
+		calls++
+		var ret [][]byte
+		for _, pass := range passes {
+			ret = append(ret, []byte(pass))
+		}
+		return ret, nil
+	})
+	k, err := k2hash.NewK2hash(f, k2hash.WithRemoveFile(true), k2hash.WithKeyProvider(provider))
+	if err != nil {
+		t.Errorf("k2hash.NewK2hash(%v) return err %v", f, err)
+		return
+	}
+	defer k.Close()
+	// 1. asked at open time
+	if calls != 1 {
+		t.Errorf("KeyProvider is called %v times at open time, want 1", calls)
+	}
+	if ok, err := k.Set("kp_key", "kp_val", k2hash.WithPassword("kp_pass")); !ok {
+		t.Errorf("k2hash.Set(kp_key) return false. want true. err %v", err)
+	}
+	if val, err := k.Get("kp_key"); err != nil || val.String() != "kp_val" {
+		t.Errorf("k2hash.Get(kp_key) = (%v, %v), want kp_val", val, err)
+	}
+	// 2. asked again on a decrypt failure
+	if ok, err := k.Set("kp_new_key", "kp_new_val", k2hash.WithPassword("kp_new_pass")); !ok {
+		t.Errorf("k2hash.Set(kp_new_key) return false. want true. err %v", err)
+	}
+	passes = []string{"kp_new_pass", "kp_pass"}
+	if val, err := k.Get("kp_new_key"); err != nil || val.String() != "kp_new_val" {
+		t.Errorf("k2hash.Get(kp_new_key) = (%v, %v), want kp_new_val", val, err)
+	}
+	if e, err := k.GetEntry("kp_key"); err != nil || string(e.Value) != "kp_val\x00" {
+		t.Errorf("k2hash.GetEntry(kp_key) = (%v, %v), want kp_val", e, err)
+	}
+	if calls != 2 {
+		t.Errorf("KeyProvider is called %v times, want 2", calls)
+	}
+	// 3. still undecryptable
+	if ok, err := k.Set("kp_other_key", "kp_other_val", k2hash.WithPassword("kp_other_pass")); !ok {
+		t.Errorf("k2hash.Set(kp_other_key) return false. want true. err %v", err)
+	}
+	if _, err := k.Get("kp_other_key"); !errors.Is(err, k2hash.ErrDecrypt) {
+		t.Errorf("k2hash.Get(kp_other_key) return err %v, want ErrDecrypt", err)
+	}
+	// reloaded at most once per second
+	if calls != 2 {
+		t.Errorf("KeyProvider is called %v times, want 2", calls)
+	}
+	// 4. failure at open time
+	failing := k2hash.KeyProviderFunc(func(ctx context.Context) ([][]byte, error) {
+		return nil, errors.New("no secret")
+	})
+	if _, err := k2hash.NewMemoryK2hash(k2hash.WithKeyProvider(failing)); err == nil {
+		t.Errorf("k2hash.NewMemoryK2hash(failing provider) return nil, want err")
+	}
+	if _, err := k2hash.NewMemoryK2hash(k2hash.WithKeyProvider(provider), k2hash.WithKeyReloadInterval(-time.Second)); !errors.Is(err, k2hash.ErrInvalid) {
+		t.Errorf("k2hash.NewMemoryK2hash(WithKeyReloadInterval(-1s)) return err %v, want ErrInvalid", err)
+	}
+}
+
+// testKeyProviderReads tests k2hash.GetMany, k2hash.Cursor.Value and k2hash.Store.Get reload the passphrases.
+func testKeyProviderReads(t *testing.T) {
+	passes := []string{"kp_pass"}
+	provider := k2hash.KeyProviderFunc(func(ctx context.Context) ([][]byte, error) {
+		var ret [][]byte
+		for _, pass := range passes {
+			ret = append(ret, []byte(pass))
+		}
+		return ret, nil
+	})
+	k, err := k2hash.NewMemoryK2hash(k2hash.WithKeyProvider(provider), k2hash.WithKeyReloadInterval(0))
+	if err != nil {
+		t.Errorf("k2hash.NewMemoryK2hash() return err %v", err)
+		return
+	}
+	defer k.Close()
+	// 1. GetMany
+	if ok, err := k.Set("kp_many", "kp_many_val", k2hash.WithPassword("kp_many_pass")); !ok {
+		t.Errorf("k2hash.Set(kp_many) return false. want true. err %v", err)
+	}
+	passes = append(passes, "kp_many_pass")
+	results, err := k.GetMany([]string{"kp_many", "kp_nokey"})
+	if err != nil || len(results) != 2 || results[0].Err != nil || results[0].Value.String() != "kp_many_val" ||
+		!errors.Is(results[1].Err, k2hash.ErrNotFound) {
+		t.Errorf("k2hash.GetMany(kp_many, kp_nokey) = (%v, %v), want kp_many_val and ErrNotFound", results, err)
+	}
+	// 2. Cursor.Value
+	if ok, err := k.Set("kp_cursor", "kp_cursor_val", k2hash.WithPassword("kp_cursor_pass")); !ok {
+		t.Errorf("k2hash.Set(kp_cursor) return false. want true. err %v", err)
+	}
+	passes = append(passes, "kp_cursor_pass")
+	found := false
+	if err := k.Iterate(func(key, val []byte) error {
+		if string(key) == "kp_cursor\x00" {
+			found = string(val) == "kp_cursor_val\x00"
+		}
+		return nil
+	}); err != nil || !found {
+		t.Errorf("k2hash.Iterate() return err %v, found kp_cursor_val %v, want true", err, found)
+	}
+	// 3. Store.Get
+	if ok, err := k.Set("kp_store", []byte("kp_store_val"), k2hash.WithPassword("kp_store_pass")); !ok {
+		t.Errorf("k2hash.Set(kp_store) return false. want true. err %v", err)
+	}
+	passes = append(passes, "kp_store_pass")
+	s := k2hash.NewStore[string, []byte](k, k2hash.RawCodec{})
+	if val, err := s.Get(context.Background(), "kp_store"); err != nil || string(val) != "kp_store_val" {
+		t.Errorf("Store.Get(kp_store) = (%q, %v), want kp_store_val", val, err)
+	}
+}
+
+// testEnvKeyProvider tests k2hash.EnvKeyProvider and k2hash.FileKeyProvider.
+func testEnvKeyProvider(t *testing.T) {
+	want := [][]byte{[]byte("env_pass1"), []byte("env_pass2")}
+	// 1. environment variables
+	t.Setenv("K2HASH_TEST_PASS1", "env_pass1")
+	t.Setenv("K2HASH_TEST_PASS2", "env_pass2")
+	env := k2hash.EnvKeyProvider{"K2HASH_TEST_PASS1", "K2HASH_TEST_UNSET", "K2HASH_TEST_PASS2"}
+	if passes, err := env.Passphrases(context.Background()); err != nil || !reflect.DeepEqual(passes, want) {
+		t.Errorf("EnvKeyProvider.Passphrases() = (%q, %v), want %q", passes, err, want)
+	}
+	if _, err := (k2hash.EnvKeyProvider{"K2HASH_TEST_UNSET"}).Passphrases(context.Background()); !errors.Is(err, k2hash.ErrInvalid) {
+		t.Errorf("EnvKeyProvider.Passphrases(unset) return err %v, want ErrInvalid", err)
+	}
+	// 2. file
+	file := "/tmp/test_keyprovider.txt"
+	if err := os.WriteFile(file, []byte("env_pass1\r\n\nenv_pass2\n"), 0600); err != nil {
+		t.Errorf("os.WriteFile(%v) return err %v", file, err)
+		return
+	}
+	defer os.Remove(file)
+	if passes, err := k2hash.FileKeyProvider(file).Passphrases(context.Background()); err != nil || !reflect.DeepEqual(passes, want) {
+		t.Errorf("FileKeyProvider.Passphrases() = (%q, %v), want %q", passes, err, want)
+	}
+	// 3. open with the file
+	k, err := k2hash.NewMemoryK2hash(k2hash.WithKeyProvider(k2hash.FileKeyProvider(file)))
+	if err != nil {
+		t.Errorf("k2hash.NewMemoryK2hash() return err %v", err)
+		return
+	}
+	defer k.Close()
+	if ok, err := k.Set("env_key", "env_val", k2hash.WithPassword("env_pass2")); !ok {
+		t.Errorf("k2hash.Set(env_key) return false. want true. err %v", err)
+	}
+	if val, err := k.Get("env_key"); err != nil || val.String() != "env_val" {
+		t.Errorf("k2hash.Get(env_key) = (%v, %v), want env_val", val, err)
+	}
+}
+
+// Local Variables:
+// c-basic-offset: 4
+// tab-width: 4
+// indent-tabs-mode: t
+// End:
+// vim600: noexpandtab sw=4 ts=4 fdm=marker
+// vim<600: noexpandtab sw=4 ts=4
@@ -0,0 +1,105 @@
+//
+// k2hash_go
+//
+// Copyright 2018 Yahoo Japan Corporation.
+//
+// Go driver for k2hash that is a NoSQL Key Value Store(KVS) library.
+// For k2hash, see https://github.com/yahoojapan/k2hash for the details.
+//
+// For the full copyright and license information, please view
+// the license file that was distributed with this source code.
+//
+// AUTHOR:   Hirotaka Wakabayashi
+// CREATE:   Sun, 18 Oct 2026
+// REVISION:
+//
+
+package k2hashtest
+
+import (
+	// #cgo CFLAGS: -g -Wall -O2 -Wall -Wextra -Wno-unused-variable -Wno-unused-parameter -I. -I/usr/include/k2hash
+	// #cgo LDFLAGS: -L/usr/lib -lk2hash
+	// #include <stdlib.h>
+	// #include "k2hash.h"
+	"C"
+)
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yahoojapan/k2hash_go/k2hash"
+)
+
+// The actual test functions are in non-_test.go files
+// so that they can use cgo (import "C").
+// These wrappers are here for gotest to find.
+
+// testNewMemoryK2hash tests k2hash.NewMemoryK2hash.
+func testNewMemoryK2hash(t *testing.T) {
+	k, err := k2hash.NewMemoryK2hash(k2hash.WithMaskBits(4))
+	if err != nil {
+		t.Errorf("k2hash.NewMemoryK2hash() return err %v", err)
+		return
+	}
+	testOpenModeArgs(k, t)
+	if _, err := k2hash.NewMemoryK2hash(k2hash.WithReadOnly(true)); !errors.Is(err, k2hash.ErrInvalid) {
+		t.Errorf("k2hash.NewMemoryK2hash(WithReadOnly(true)) return err %v, want ErrInvalid", err)
+	}
+}
+
+// testNewTempK2hash tests k2hash.NewTempK2hash.
+func testNewTempK2hash(t *testing.T) {
+	pattern := filepath.Join(os.TempDir(), "k2hash_*")
+	before, _ := filepath.Glob(pattern)
+	k, err := k2hash.NewTempK2hash("")
+	if err != nil {
+		t.Errorf("k2hash.NewTempK2hash() return err %v", err)
+		return
+	}
+	if dirs, _ := filepath.Glob(pattern); len(dirs) != len(before)+1 {
+		t.Errorf("k2hash.NewTempK2hash() creates %v directories, want 1", len(dirs)-len(before))
+	}
+	testOpenModeArgs(k, t)
+	if after, _ := filepath.Glob(pattern); len(after) != len(before) {
+		t.Errorf("the directory of k2hash.NewTempK2hash() remains after Close: %v", after)
+	}
+	f := "/tmp/temp.k2h"
+	if k, err = k2hash.NewTempK2hash(f); err != nil {
+		t.Errorf("k2hash.NewTempK2hash(%v) return err %v", f, err)
+		return
+	}
+	testOpenModeArgs(k, t)
+	if _, err := os.Stat(f); !os.IsNotExist(err) {
+		t.Errorf("os.Stat(%v) return err %v after Close, want not exist", f, err)
+	}
+}
+
+func testOpenModeArgs(k *k2hash.K2hash, t *testing.T) {
+	if ok, err := k.Set("openmode_key", "openmode_val"); !ok {
+		t.Errorf("k2hash.Set(openmode_key) return false. want true. err %v", err)
+	}
+	if val, err := k.Get("openmode_key"); err != nil || val.String() != "openmode_val" {
+		t.Errorf("k2hash.Get(openmode_key) = (%v, %v), want openmode_val", val, err)
+	}
+	count := 0
+	for range k.Keys() {
+		count++
+	}
+	if count != 1 {
+		t.Errorf("k2hash.Keys() yields %v keys, want 1", count)
+	}
+	if ok, err := k.Close(); !ok {
+		t.Errorf("k2hash.Close() return false. want true. err %v", err)
+	}
+}
+
+// Local Variables:
+// c-basic-offset: 4
+// tab-width: 4
+// indent-tabs-mode: t
+// End:
+// vim600: noexpandtab sw=4 ts=4 fdm=marker
+// vim<600: noexpandtab sw=4 ts=4
@@ -0,0 +1,133 @@
+//
+// k2hash_go
+//
+// Copyright 2018 Yahoo Japan Corporation.
+//
+// Go driver for k2hash that is a NoSQL Key Value Store(KVS) library.
+// For k2hash, see https://github.com/yahoojapan/k2hash for the details.
+//
+// For the full copyright and license information, please view
+// the license file that was distributed with this source code.
+//
+// AUTHOR:   Hirotaka Wakabayashi
+// CREATE:   Sun, 18 Oct 2026
+// REVISION:
+//
+
+package k2hashtest
+
+import (
+	// #cgo CFLAGS: -g -Wall -O2 -Wall -Wextra -Wno-unused-variable -Wno-unused-parameter -I. -I/usr/include/k2hash
+	// #cgo LDFLAGS: -L/usr/lib -lk2hash
+	// #include <stdlib.h>
+	// #include "k2hash.h"
+	"C"
+)
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yahoojapan/k2hash_go/k2hash"
+)
+
+// The actual test functions are in non-_test.go files
+// so that they can use cgo (import "C").
+// These wrappers are here for gotest to find.
+
+// testK2hashOptions tests the options of k2hash.NewK2hash.
+func testK2hashOptions(t *testing.T) {
+	// 1. invalid options
+	if k, err := k2hash.NewK2hash("/tmp/test.k2h", k2hash.WithMaskBits(0)); err == nil {
+		k.Close()
+		t.Errorf("k2hash.NewK2hash(WithMaskBits(0)) return nil err. want an error")
+	}
+	if k, err := k2hash.NewK2hash("/tmp/test.k2h", k2hash.WithPageSize(-1)); err == nil {
+		k.Close()
+		t.Errorf("k2hash.NewK2hash(WithPageSize(-1)) return nil err. want an error")
+	}
+	// 2. read only
+	k, err := k2hash.NewK2hash("/tmp/test.k2h", k2hash.WithReadOnly(true), k2hash.WithCloseWait(time.Second))
+	if err != nil {
+		t.Errorf("k2hash.NewK2hash(WithReadOnly(true)) return err %v", err)
+		return
+	}
+	defer k.Close()
+	if ok, _ := k.Set("options_readonly_key", "val"); ok {
+		t.Errorf("k2hash.Set() on a read only file return true. want false")
+	}
+}
+
+// testParamsOptions tests the options of k2hash.Set and k2hash.Remove.
+func testParamsOptions(t *testing.T) {
+	k, err := k2hash.NewK2hash("/tmp/test.k2h")
+	if err != nil {
+		t.Errorf("k2hash.NewK2hash(/tmp/test.k2h) return err %v", err)
+		return
+	}
+	defer k.Close()
+	// 1. negative expiration
+	for _, d := range []time.Duration{-time.Second, -1500 * time.Millisecond, 0} {
+		if ok, err := k.Set("options_key", "val", k2hash.WithExpire(d)); ok || !errors.Is(err, k2hash.ErrInvalid) {
+			t.Errorf("k2hash.Set(WithExpire(%v)) = (%v, %v), want ErrInvalid", d, ok, err)
+		}
+	}
+	if ok, err := k.BeginTx("/tmp/test_options.log", k2hash.WithTxExpire(-time.Second)); ok || !errors.Is(err, k2hash.ErrInvalid) {
+		t.Errorf("k2hash.BeginTx(WithTxExpire(-1s)) = (%v, %v), want ErrInvalid", ok, err)
+	}
+	// 2. expiration
+	if ok, err := k.Set("options_key", "val", k2hash.WithExpire(time.Hour)); !ok {
+		t.Errorf("k2hash.Set(WithExpire(1h)) return false. want true. err %v", err)
+	}
+	if val, err := k.Get("options_key"); err != nil || val.String() != "val" {
+		t.Errorf("k2hash.Get(options_key) = (%v, %v), want val", val, err)
+	}
+	// 3. remove with subkey
+	if ok, err := k.AddSubKey("options_key", "options_subkey", "subval"); !ok {
+		t.Errorf("k2hash.AddSubKey() return false. want true. err %v", err)
+	}
+	if ok, err := k.Remove("options_key", k2hash.WithRemoveSubKey("options_subkey")); !ok {
+		t.Errorf("k2hash.Remove(WithRemoveSubKey()) return false. want true. err %v", err)
+	}
+	if skeys, err := k.GetSubKeys("options_key"); err != nil || skeys == nil || len(skeys) != 0 {
+		t.Errorf("k2hash.GetSubKeys() = (%v, %v), want no subkeys", skeys, err)
+	}
+	if ok, err := k.Remove("options_key", k2hash.WithRemoveAll()); !ok {
+		t.Errorf("k2hash.Remove(WithRemoveAll()) return false. want true. err %v", err)
+	}
+}
+
+// testQueueOptions tests the options of k2hash.NewQueue.
+func testQueueOptions(t *testing.T) {
+	k, err := k2hash.NewK2hash("/tmp/test.k2h")
+	if err != nil {
+		t.Errorf("k2hash.NewK2hash(/tmp/test.k2h) return err %v", err)
+		return
+	}
+	defer k.Close()
+	q, err := k2hash.NewQueue(k, k2hash.WithQueueFIFO(false), k2hash.WithQueuePrefix("options_lifo_"))
+	if err != nil {
+		t.Errorf("k2hash.NewQueue(WithQueueFIFO(false)) return err %v", err)
+		return
+	}
+	defer q.Free()
+	for _, v := range []string{"first", "second"} {
+		if ok, err := q.Push(v); !ok {
+			t.Errorf("Queue.Push(%v) return false. wants true. err %v", v, err)
+		}
+	}
+	for _, want := range []string{"second", "first"} {
+		if v, err := q.Pop(); v != want {
+			t.Errorf("Queue.Pop() = (%v, %v), want %v", v, err, want)
+		}
+	}
+}
+
+// Local Variables:
+// c-basic-offset: 4
+// tab-width: 4
+// indent-tabs-mode: t
+// End:
+// vim600: noexpandtab sw=4 ts=4 fdm=marker
+// vim<600: noexpandtab sw=4 ts=4
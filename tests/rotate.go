@@ -0,0 +1,121 @@
+//
+// k2hash_go
+//
+// Copyright 2018 Yahoo Japan Corporation.
+//
+// Go driver for k2hash that is a NoSQL Key Value Store(KVS) library.
+// For k2hash, see https://github.com/yahoojapan/k2hash for the details.
+//
+// For the full copyright and license information, please view
+// the license file that was distributed with this source code.
+//
+// AUTHOR:   Hirotaka Wakabayashi
+// CREATE:   Sun, 18 Oct 2026
+// REVISION:
+//
+
+package k2hashtest
+
+import (
+	// #cgo CFLAGS: -g -Wall -O2 -Wall -Wextra -Wno-unused-variable -Wno-unused-parameter -I. -I/usr/include/k2hash
+	// #cgo LDFLAGS: -L/usr/lib -lk2hash
+	// #include <stdlib.h>
+	// #include "k2hash.h"
+	"C"
+)
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/yahoojapan/k2hash_go/k2hash"
+)
+
+// The actual test functions are in non-_test.go files
+// so that they can use cgo (import "C").
+// These wrappers are here for gotest to find.
+
+// testRotateEncryption tests k2hash.RotateEncryption.
+func testRotateEncryption(t *testing.T) {
+	f := "/tmp/test.k2h"
+	k, err := k2hash.NewK2hash(f, k2hash.WithRemoveFile(true))
+	if err != nil {
+		t.Errorf("k2hash.NewK2hash(%v) return err %v", f, err)
+		return
+	}
+	defer k.Close()
+	// 1. an encrypted key with an attribute and a plain key
+	if ok, err := k.Set("rotate_key", "rotate_val", k2hash.WithPassword("old_pass")); !ok {
+		t.Errorf("k2hash.Set(rotate_key) return false. want true. err %v", err)
+	}
+	if ok, err := k.AddAttr("rotate_key", "rotate_attr", "rotate_attr_val"); !ok {
+		t.Errorf("k2hash.AddAttr(rotate_key) return false. want true. err %v", err)
+	}
+	if ok, err := k.Set("rotate_plain", "rotate_plain_val"); !ok {
+		t.Errorf("k2hash.Set(rotate_plain) return false. want true. err %v", err)
+	}
+	// 2. rotate
+	checkpoint := "/tmp/test_rotate.checkpoint"
+	calls := 0
+	progress, err := k.RotateEncryption("old_pass", "new_pass",
+		k2hash.WithRotateCheckpoint(checkpoint),
+		k2hash.WithRotateProgress(func(k2hash.RotateProgress) { calls++ }))
+	if err != nil {
+		t.Errorf("k2hash.RotateEncryption() return err %v", err)
+		return
+	}
+	if progress.Rotated != 1 || progress.Scanned != calls || progress.Scanned != progress.Rotated+progress.Skipped {
+		t.Errorf("k2hash.RotateEncryption() = %v with %v calls, want 1 rotated", progress, calls)
+	}
+	if _, err := os.Stat(checkpoint); !os.IsNotExist(err) {
+		t.Errorf("checkpoint %v exists after completion. err %v", checkpoint, err)
+	}
+	// 3. the new passphrase and the attributes
+	if val, err := k.Get("rotate_key", k2hash.WithPassword("new_pass")); err != nil || val.String() != "rotate_val" {
+		t.Errorf("k2hash.Get(rotate_key, new_pass) = (%v, %v), want rotate_val", val, err)
+	}
+	if val, err := k.GetAttr("rotate_key", "rotate_attr"); err != nil || string(val) != "rotate_attr_val\x00" {
+		t.Errorf("k2hash.GetAttr(rotate_key, rotate_attr) = (%q, %v), want rotate_attr_val", val, err)
+	}
+	if val, err := k.Get("rotate_plain"); err != nil || val.String() != "rotate_plain_val" {
+		t.Errorf("k2hash.Get(rotate_plain) = (%v, %v), want rotate_plain_val", val, err)
+	}
+	// 4. rerun skips or rewrites rotated keys
+	if _, err := k.RotateEncryption("old_pass", "new_pass"); err != nil {
+		t.Errorf("k2hash.RotateEncryption() again return err %v", err)
+	}
+	if val, err := k.Get("rotate_key", k2hash.WithPassword("new_pass")); err != nil || val.String() != "rotate_val" {
+		t.Errorf("k2hash.Get(rotate_key, new_pass) = (%v, %v), want rotate_val", val, err)
+	}
+	// 5. keys over pages are rotated once
+	for i := 0; i < 250; i++ {
+		key := fmt.Sprintf("rotate_page%v", i)
+		if ok, err := k.Set(key, "rotate_val", k2hash.WithPassword("new_pass")); !ok {
+			t.Errorf("k2hash.Set(%v) return false. want true. err %v", key, err)
+		}
+	}
+	progress, err = k.RotateEncryption("new_pass", "next_pass")
+	if err != nil || progress.Rotated != 251 || progress.Scanned != 252 {
+		t.Errorf("k2hash.RotateEncryption(next_pass) = (%v, %v), want 251 rotated in 252 keys", progress, err)
+	}
+	for i := 0; i < 250; i++ {
+		key := fmt.Sprintf("rotate_page%v", i)
+		if val, err := k.Get(key, k2hash.WithPassword("next_pass")); err != nil || val.String() != "rotate_val" {
+			t.Errorf("k2hash.Get(%v, next_pass) = (%v, %v), want rotate_val", key, val, err)
+		}
+	}
+	// 6. empty passphrase
+	if _, err := k.RotateEncryption("new_pass", ""); !errors.Is(err, k2hash.ErrInvalid) {
+		t.Errorf("k2hash.RotateEncryption(empty) return err %v, want ErrInvalid", err)
+	}
+}
+
+// Local Variables:
+// c-basic-offset: 4
+// tab-width: 4
+// indent-tabs-mode: t
+// End:
+// vim600: noexpandtab sw=4 ts=4 fdm=marker
+// vim<600: noexpandtab sw=4 ts=4
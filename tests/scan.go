@@ -0,0 +1,102 @@
+//
+// k2hash_go
+//
+// Copyright 2018 Yahoo Japan Corporation.
+//
+// Go driver for k2hash that is a NoSQL Key Value Store(KVS) library.
+// For k2hash, see https://github.com/yahoojapan/k2hash for the details.
+//
+// For the full copyright and license information, please view
+// the license file that was distributed with this source code.
+//
+// AUTHOR:   Hirotaka Wakabayashi
+// CREATE:   Sun, 18 Oct 2026
+// REVISION:
+//
+
+package k2hashtest
+
+import (
+	// #cgo CFLAGS: -g -Wall -O2 -Wall -Wextra -Wno-unused-variable -Wno-unused-parameter -I. -I/usr/include/k2hash
+	// #cgo LDFLAGS: -L/usr/lib -lk2hash
+	// #include <stdlib.h>
+	// #include "k2hash.h"
+	"C"
+)
+
+import (
+	"regexp"
+	"sort"
+	"testing"
+
+	"github.com/yahoojapan/k2hash_go/k2hash"
+)
+
+// The actual test functions are in non-_test.go files
+// so that they can use cgo (import "C").
+// These wrappers are here for gotest to find.
+
+// testScan tests k2hash.ScanPrefix and k2hash.ScanMatch methods.
+func testScan(t *testing.T) {
+	// 1. define test data.
+	testData := map[string]string{
+		"tenant1:user:1":  "alice",
+		"tenant1:user:2":  "bob",
+		"tenant1:group:1": "admin",
+		"tenant2:user:1":  "carol",
+	}
+	k, err := k2hash.NewK2hash("/tmp/scan.k2h", k2hash.WithRemoveFile(true))
+	if err != nil {
+		t.Errorf("k2hash.NewK2hash(/tmp/scan.k2h) return err %v", err)
+		return
+	}
+	defer k.Close()
+	for key, val := range testData {
+		if ok, err := k.Set(key, val); !ok {
+			t.Errorf("k2hash.Set(%v, %v) return false. want true. err %v", key, val, err)
+		}
+	}
+	keys := func(results []k2hash.ScanResult) []string {
+		s := []string{}
+		for _, r := range results {
+			s = append(s, string(r.Key[:len(r.Key)-1]))
+		}
+		sort.Strings(s)
+		return s
+	}
+	// 2. ScanPrefix
+	results, err := k.ScanPrefix("tenant1:", k2hash.WithScanValues())
+	if err != nil {
+		t.Errorf("k2hash.ScanPrefix(tenant1:) return err %v", err)
+	}
+	if got := keys(results); len(got) != 3 || got[0] != "tenant1:group:1" {
+		t.Errorf("k2hash.ScanPrefix(tenant1:) = %v", got)
+	}
+	for _, r := range results {
+		if string(r.Value[:len(r.Value)-1]) != testData[string(r.Key[:len(r.Key)-1])] {
+			t.Errorf("k2hash.ScanPrefix(tenant1:) returns value %v of %v", r.Value, r.Key)
+		}
+	}
+	// 3. ScanMatch with a glob
+	results, err = k.ScanMatch("tenant*:user:1")
+	if got := keys(results); err != nil || len(got) != 2 || got[0] != "tenant1:user:1" || got[1] != "tenant2:user:1" {
+		t.Errorf("k2hash.ScanMatch(tenant*:user:1) = (%v, %v)", got, err)
+	}
+	// 4. ScanMatch with a regexp
+	results, err = k.ScanMatch(regexp.MustCompile(`^tenant1:user:\d+$`))
+	if got := keys(results); err != nil || len(got) != 2 {
+		t.Errorf("k2hash.ScanMatch(regexp) = (%v, %v)", got, err)
+	}
+	// 5. invalid pattern
+	if _, err := k.ScanMatch("["); err == nil {
+		t.Errorf("k2hash.ScanMatch([) return nil err. want an error")
+	}
+}
+
+// Local Variables:
+// c-basic-offset: 4
+// tab-width: 4
+// indent-tabs-mode: t
+// End:
+// vim600: noexpandtab sw=4 ts=4 fdm=marker
+// vim<600: noexpandtab sw=4 ts=4
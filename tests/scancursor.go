@@ -0,0 +1,149 @@
+//
+// k2hash_go
+//
+// Copyright 2018 Yahoo Japan Corporation.
+//
+// Go driver for k2hash that is a NoSQL Key Value Store(KVS) library.
+// For k2hash, see https://github.com/yahoojapan/k2hash for the details.
+//
+// For the full copyright and license information, please view
+// the license file that was distributed with this source code.
+//
+// AUTHOR:   Hirotaka Wakabayashi
+// CREATE:   Sun, 18 Oct 2026
+// REVISION:
+//
+
+package k2hashtest
+
+import (
+	// #cgo CFLAGS: -g -Wall -O2 -Wall -Wextra -Wno-unused-variable -Wno-unused-parameter -I. -I/usr/include/k2hash
+	// #cgo LDFLAGS: -L/usr/lib -lk2hash
+	// #include <stdlib.h>
+	// #include "k2hash.h"
+	"C"
+)
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/yahoojapan/k2hash_go/k2hash"
+)
+
+// The actual test functions are in non-_test.go files
+// so that they can use cgo (import "C").
+// These wrappers are here for gotest to find.
+
+// testScanCursor tests k2hash.ScanCursor with resuming from tokens.
+func testScanCursor(t *testing.T) {
+	k, err := k2hash.NewK2hash("/tmp/scancursor.k2h", k2hash.WithRemoveFile(true))
+	if err != nil {
+		t.Errorf("k2hash.NewK2hash(/tmp/scancursor.k2h) return err %v", err)
+		return
+	}
+	defer k.Close()
+	total := 25
+	for i := 0; i < total; i++ {
+		key := fmt.Sprintf("scancursor_key%v", i)
+		if ok, err := k.Set(key, "val"); !ok {
+			t.Errorf("k2hash.Set(%v) return false. want true. err %v", key, err)
+		}
+	}
+	// 1. read pages by resuming from tokens
+	found := map[string]bool{}
+	token := ""
+	for pages := 0; pages < total; pages++ {
+		c, err := k.ResumeScanCursor(token)
+		if err != nil {
+			t.Errorf("k2hash.ResumeScanCursor(%v) return err %v", token, err)
+			return
+		}
+		results, err := c.Next(10)
+		if err != nil {
+			t.Errorf("ScanCursor.Next(10) return err %v", err)
+			return
+		}
+		for _, r := range results {
+			if found[string(r.Key)] {
+				t.Errorf("ScanCursor.Next(10) returns %v twice", string(r.Key))
+			}
+			found[string(r.Key)] = true
+		}
+		if token = c.Token(); c.Done() {
+			break
+		}
+	}
+	if len(found) != total {
+		t.Errorf("ScanCursor returns %v keys, want %v", len(found), total)
+	}
+	// 2. the token of a completed scan
+	if token == "" {
+		t.Errorf("ScanCursor.Token() of a completed scan is empty")
+	}
+	if c, err := k.ResumeScanCursor(token); err != nil || !c.Done() {
+		t.Errorf("k2hash.ResumeScanCursor(done) = (%v, %v), want a done cursor", c, err)
+	} else if results, err := c.Next(10); err != nil || len(results) != 0 {
+		t.Errorf("ScanCursor.Next(10) of a completed scan = (%v, %v), want no keys", results, err)
+	}
+	// 3. the token is bounded and doesn't contain keys
+	c := k.NewScanCursor()
+	results, err := c.Next(10)
+	if err != nil || len(results) != 10 {
+		t.Errorf("ScanCursor.Next(10) = (%v, %v), want 10 keys", len(results), err)
+		return
+	}
+	c.Close()
+	if b, err := base64.RawURLEncoding.DecodeString(c.Token()); err != nil || len(b) > 28 || bytes.Contains(b, []byte("scancursor_key")) {
+		t.Errorf("ScanCursor.Token() = %q, want a bounded token without keys", b)
+	}
+	// 4. another handle finds the last key from the first key
+	k2, err := k2hash.NewK2hash("/tmp/scancursor.k2h")
+	if err != nil {
+		t.Errorf("k2hash.NewK2hash(/tmp/scancursor.k2h) return err %v", err)
+		return
+	}
+	defer k2.Close()
+	testScanCursorRest(k2, c.Token(), total-10, total-10, t)
+	// 5. the last key is removed between pages
+	if ok, err := k.Remove(results[9].Key); !ok {
+		t.Errorf("k2hash.Remove(%v) return false. want true. err %v", string(results[9].Key), err)
+	}
+	testScanCursorRest(k2, c.Token(), total-11, total-10, t)
+	// 6. malformed token
+	if _, err := k.ResumeScanCursor("!"); err == nil {
+		t.Errorf("k2hash.ResumeScanCursor(!) return nil err. want an error")
+	}
+}
+
+// testScanCursorRest resumes a scan from the token and checks the number of the rest keys.
+func testScanCursorRest(k *k2hash.K2hash, token string, min int, max int, t *testing.T) {
+	c, err := k.ResumeScanCursor(token)
+	if err != nil {
+		t.Errorf("k2hash.ResumeScanCursor() return err %v", err)
+		return
+	}
+	defer c.Close()
+	rest := 0
+	for !c.Done() {
+		results, err := c.Next(10)
+		if err != nil {
+			t.Errorf("ScanCursor.Next(10) return err %v", err)
+			return
+		}
+		rest += len(results)
+	}
+	if rest < min || rest > max {
+		t.Errorf("ScanCursor returns %v keys after the token, want %v to %v", rest, min, max)
+	}
+}
+
+// Local Variables:
+// c-basic-offset: 4
+// tab-width: 4
+// indent-tabs-mode: t
+// End:
+// vim600: noexpandtab sw=4 ts=4 fdm=marker
+// vim<600: noexpandtab sw=4 ts=4
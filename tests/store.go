@@ -0,0 +1,133 @@
+//
+// k2hash_go
+//
+// Copyright 2018 Yahoo Japan Corporation.
+//
+// Go driver for k2hash that is a NoSQL Key Value Store(KVS) library.
+// For k2hash, see https://github.com/yahoojapan/k2hash for the details.
+//
+// For the full copyright and license information, please view
+// the license file that was distributed with this source code.
+//
+// AUTHOR:   Hirotaka Wakabayashi
+// CREATE:   Sun, 18 Oct 2026
+// REVISION:
+//
+
+package k2hashtest
+
+import (
+	// #cgo CFLAGS: -g -Wall -O2 -Wall -Wextra -Wno-unused-variable -Wno-unused-parameter -I. -I/usr/include/k2hash
+	// #cgo LDFLAGS: -L/usr/lib -lk2hash
+	// #include <stdlib.h>
+	// #include "k2hash.h"
+	"C"
+)
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/yahoojapan/k2hash_go/k2hash"
+)
+
+// The actual test functions are in non-_test.go files
+// so that they can use cgo (import "C").
+// These wrappers are here for gotest to find.
+
+type storeUser struct {
+	Name string
+	Age  int
+}
+
+// testStore tests k2hash.Store.
+func testStore(t *testing.T) {
+	f := "/tmp/test.k2h"
+	k, err := k2hash.NewK2hash(f, k2hash.WithRemoveFile(true))
+	if err != nil {
+		t.Errorf("k2hash.NewK2hash(%v) return err %v", f, err)
+		return
+	}
+	defer k.Close()
+	ctx := context.Background()
+	user := storeUser{Name: "alice", Age: 20}
+	// 1. codecs
+	for _, codec := range []k2hash.Codec{nil, k2hash.JSONCodec{}, k2hash.GobCodec{}} {
+		s := k2hash.NewStore[string, storeUser](k, codec)
+		if err := s.Put(ctx, "store_user", user); err != nil {
+			t.Errorf("Store.Put(store_user) with %T return err %v", codec, err)
+			continue
+		}
+		if got, err := s.Get(ctx, "store_user"); err != nil || !reflect.DeepEqual(got, user) {
+			t.Errorf("Store.Get(store_user) with %T = (%v, %v), want %v", codec, got, err, user)
+		}
+	}
+	raw := k2hash.NewStore[[]byte, []byte](k, k2hash.RawCodec{})
+	val := []byte{0x00, 0x01, 0xff}
+	if err := raw.Put(ctx, []byte("store_raw"), val); err != nil {
+		t.Errorf("Store.Put(store_raw) return err %v", err)
+	}
+	if got, err := raw.Get(ctx, []byte("store_raw")); err != nil || !reflect.DeepEqual(got, val) {
+		t.Errorf("Store.Get(store_raw) = (%v, %v), want %v", got, err, val)
+	}
+	// 2. a string key is compatible with Get
+	if _, err := k.Get("store_user"); err != nil {
+		t.Errorf("k2hash.Get(store_user) return err %v", err)
+	}
+	// 3. codec errors and storage errors
+	s := k2hash.NewStore[string, storeUser](k, k2hash.JSONCodec{})
+	if ok, err := k.Set("store_broken", []byte("{broken")); !ok {
+		t.Errorf("k2hash.Set(store_broken) return false. want true. err %v", err)
+	}
+	_, err = s.Get(ctx, "store_broken")
+	var codecErr *k2hash.CodecError
+	if !errors.As(err, &codecErr) || !errors.Is(err, k2hash.ErrCodec) || errors.Is(err, k2hash.ErrNotFound) {
+		t.Errorf("Store.Get(store_broken) return err %v, want CodecError", err)
+	}
+	_, err = s.Get(ctx, "store_nokey")
+	if !errors.Is(err, k2hash.ErrNotFound) || errors.Is(err, k2hash.ErrCodec) {
+		t.Errorf("Store.Get(store_nokey) return err %v, want ErrNotFound", err)
+	}
+	if err := s.Delete(ctx, "store_user"); err != nil {
+		t.Errorf("Store.Delete(store_user) return err %v", err)
+	}
+	if _, err := s.Get(ctx, "store_user"); !errors.Is(err, k2hash.ErrNotFound) {
+		t.Errorf("Store.Get(store_user) return err %v, want ErrNotFound", err)
+	}
+}
+
+// testStorePool tests Store.Get returns the buffer to the pool given by k2hash.WithBufferPool.
+func testStorePool(t *testing.T) {
+	ctx := context.Background()
+	val := bytes.Repeat([]byte("v"), 1024)
+	var allocs []float64
+	for _, options := range [][]func(*k2hash.K2hash){{k2hash.WithBufferPool(k2hash.NewBufferPool(2048))}, nil} {
+		k, err := k2hash.NewMemoryK2hash(options...)
+		if err != nil {
+			t.Errorf("k2hash.NewMemoryK2hash() return err %v", err)
+			return
+		}
+		defer k.Close()
+		s := k2hash.NewStore[[]byte, []byte](k, k2hash.RawCodec{})
+		if err := s.Put(ctx, []byte("store_pool"), val); err != nil {
+			t.Errorf("Store.Put(store_pool) return err %v", err)
+		}
+		allocs = append(allocs, testing.AllocsPerRun(100, func() {
+			s.Get(ctx, []byte("store_pool"))
+		}))
+	}
+	if allocs[0] >= allocs[1] {
+		t.Errorf("Store.Get with a pool allocates %v times, want less than %v", allocs[0], allocs[1])
+	}
+}
+
+// Local Variables:
+// c-basic-offset: 4
+// tab-width: 4
+// indent-tabs-mode: t
+// End:
+// vim600: noexpandtab sw=4 ts=4 fdm=marker
+// vim<600: noexpandtab sw=4 ts=4
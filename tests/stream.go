@@ -0,0 +1,155 @@
+//
+// k2hash_go
+//
+// Copyright 2018 Yahoo Japan Corporation.
+//
+// Go driver for k2hash that is a NoSQL Key Value Store(KVS) library.
+// For k2hash, see https://github.com/yahoojapan/k2hash for the details.
+//
+// For the full copyright and license information, please view
+// the license file that was distributed with this source code.
+//
+// AUTHOR:   Hirotaka Wakabayashi
+// CREATE:   Sun, 18 Oct 2026
+// REVISION:
+//
+
+package k2hashtest
+
+import (
+	// #cgo CFLAGS: -g -Wall -O2 -Wall -Wextra -Wno-unused-variable -Wno-unused-parameter -I. -I/usr/include/k2hash
+	// #cgo LDFLAGS: -L/usr/lib -lk2hash
+	// #include <stdlib.h>
+	// #include "k2hash.h"
+	"C"
+)
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/yahoojapan/k2hash_go/k2hash"
+)
+
+// The actual test functions are in non-_test.go files
+// so that they can use cgo (import "C").
+// These wrappers are here for gotest to find.
+
+// testOpenReader tests k2hash.OpenReader.
+func testOpenReader(t *testing.T) {
+	f := "/tmp/test.k2h"
+	k, err := k2hash.NewK2hash(f, k2hash.WithRemoveFile(true))
+	if err != nil {
+		t.Errorf("k2hash.NewK2hash(%v) return err %v", f, err)
+		return
+	}
+	defer k.Close()
+	key := []byte("stream_key")
+	val := bytes.Repeat([]byte("0123456789"), 100)
+	if ok, err := k.Set(key, val); !ok {
+		t.Errorf("k2hash.Set(%v) return false. want true. err %v", key, err)
+		return
+	}
+	// 1. read the whole value in small chunks
+	r, err := k.OpenReader(key)
+	if err != nil {
+		t.Errorf("k2hash.OpenReader(%v) return err %v", key, err)
+		return
+	}
+	defer r.Close()
+	buf := make([]byte, 7)
+	var got []byte
+	for {
+		n, err := r.Read(buf)
+		got = append(got, buf[:n]...)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Errorf("Reader.Read() return err %v", err)
+			return
+		}
+	}
+	if !bytes.Equal(got, val) {
+		t.Errorf("Reader.Read() got %v bytes, want %v bytes", len(got), len(val))
+	}
+	// 2. seek and read partially
+	if pos, err := r.Seek(-5, io.SeekEnd); err != nil || pos != int64(len(val)-5) {
+		t.Errorf("Reader.Seek(-5, io.SeekEnd) = (%v, %v), want %v", pos, err, len(val)-5)
+	}
+	if got, err := io.ReadAll(r); err != nil || string(got) != "56789" {
+		t.Errorf("io.ReadAll(Reader) = (%v, %v), want 56789", string(got), err)
+	}
+	if _, err := r.Seek(-1, io.SeekStart); !errors.Is(err, k2hash.ErrInvalid) {
+		t.Errorf("Reader.Seek(-1, io.SeekStart) return err %v, want ErrInvalid", err)
+	}
+	// 3. closed reader
+	if err := r.Close(); err != nil {
+		t.Errorf("Reader.Close() return err %v", err)
+	}
+	if _, err := r.Read(buf); !errors.Is(err, k2hash.ErrClosed) {
+		t.Errorf("Reader.Read() after Close return err %v, want ErrClosed", err)
+	}
+	// 4. no such key
+	if _, err := k.OpenReader("stream_nokey"); !errors.Is(err, k2hash.ErrNotFound) {
+		t.Errorf("k2hash.OpenReader(stream_nokey) return err %v, want ErrNotFound", err)
+	}
+}
+
+// testOpenWriter tests k2hash.OpenWriter.
+func testOpenWriter(t *testing.T) {
+	f := "/tmp/test.k2h"
+	k, err := k2hash.NewK2hash(f, k2hash.WithRemoveFile(true))
+	if err != nil {
+		t.Errorf("k2hash.NewK2hash(%v) return err %v", f, err)
+		return
+	}
+	defer k.Close()
+	key := []byte("stream_key")
+	// 1. write a new value
+	w, err := k.OpenWriter(key)
+	if err != nil {
+		t.Errorf("k2hash.OpenWriter(%v) return err %v", key, err)
+		return
+	}
+	for _, chunk := range []string{"hello", " ", "world"} {
+		if _, err := io.WriteString(w, chunk); err != nil {
+			t.Errorf("Writer.Write(%v) return err %v", chunk, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Errorf("Writer.Close() return err %v", err)
+	}
+	if val, err := k.Get(key); err != nil || string(val.Bytes()) != "hello world" {
+		t.Errorf("k2hash.Get(%v) = (%v, %v), want hello world", key, val, err)
+	}
+	// 2. partial update
+	w, err = k.OpenWriter(key)
+	if err != nil {
+		t.Errorf("k2hash.OpenWriter(%v) return err %v", key, err)
+		return
+	}
+	defer w.Close()
+	if _, err := w.(io.Seeker).Seek(6, io.SeekStart); err != nil {
+		t.Errorf("Writer.Seek(6, io.SeekStart) return err %v", err)
+	}
+	if _, err := io.WriteString(w, "WORLD"); err != nil {
+		t.Errorf("Writer.Write(WORLD) return err %v", err)
+	}
+	if _, err := w.(io.WriterAt).WriteAt([]byte("H"), 0); err != nil {
+		t.Errorf("Writer.WriteAt(H, 0) return err %v", err)
+	}
+	if val, err := k.Get(key); err != nil || string(val.Bytes()) != "Hello WORLD" {
+		t.Errorf("k2hash.Get(%v) = (%v, %v), want Hello WORLD", key, val, err)
+	}
+}
+
+// Local Variables:
+// c-basic-offset: 4
+// tab-width: 4
+// indent-tabs-mode: t
+// End:
+// vim600: noexpandtab sw=4 ts=4 fdm=marker
+// vim<600: noexpandtab sw=4 ts=4
@@ -0,0 +1,170 @@
+//
+// k2hash_go
+//
+// Copyright 2018 Yahoo Japan Corporation.
+//
+// Go driver for k2hash that is a NoSQL Key Value Store(KVS) library.
+// For k2hash, see https://github.com/yahoojapan/k2hash for the details.
+//
+// For the full copyright and license information, please view
+// the license file that was distributed with this source code.
+//
+// AUTHOR:   Hirotaka Wakabayashi
+// CREATE:   Sun, 18 Oct 2026
+// REVISION:
+//
+
+package k2hashtest
+
+import (
+	// #cgo CFLAGS: -g -Wall -O2 -Wall -Wextra -Wno-unused-variable -Wno-unused-parameter -I. -I/usr/include/k2hash
+	// #cgo LDFLAGS: -L/usr/lib -lk2hash
+	// #include <stdlib.h>
+	// #include "k2hash.h"
+	"C"
+)
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/yahoojapan/k2hash_go/k2hash"
+)
+
+// The actual test functions are in non-_test.go files
+// so that they can use cgo (import "C").
+// These wrappers are here for gotest to find.
+
+// testSweep tests k2hash.Sweep.
+func testSweep(t *testing.T) {
+	k, err := k2hash.NewMemoryK2hash()
+	if err != nil {
+		t.Errorf("k2hash.NewMemoryK2hash() return err %v", err)
+		return
+	}
+	defer k.Close()
+	testSweepArgs(k, []string{"sweep_key1", "sweep_key2"}, []string{"sweep_key3"}, t)
+	n, err := k.Sweep(context.Background())
+	if err != nil || n != 2 {
+		t.Errorf("k2hash.Sweep() = (%v, %v), want 2", n, err)
+	}
+	if _, err := k.Get("sweep_key3"); err != nil {
+		t.Errorf("k2hash.Get(sweep_key3) return err %v", err)
+	}
+	stats := k.SweepStats()
+	if stats.Scanned != 3 || stats.Removed != 2 || stats.Runs != 1 || stats.LastRun.IsZero() || stats.LastErr != nil {
+		t.Errorf("k2hash.SweepStats() = %v, want 3 scanned and 2 removed", stats)
+	}
+	// canceled
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := k.Sweep(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("k2hash.Sweep() return err %v, want context.Canceled", err)
+	}
+}
+
+// testSweepExtended tests k2hash.Sweep keeps a key which is set again after the scan.
+func testSweepExtended(t *testing.T) {
+	// A key is scanned every 250ms, so that the keys are set again between the scan and the removal.
+	k, err := k2hash.NewMemoryK2hash(k2hash.WithSweepRate(4))
+	if err != nil {
+		t.Errorf("k2hash.NewMemoryK2hash() return err %v", err)
+		return
+	}
+	defer k.Close()
+	keys := []string{"sweep_key1", "sweep_key2"}
+	testSweepArgs(k, keys, nil, t)
+	go func() {
+		time.Sleep(375 * time.Millisecond)
+		for _, key := range keys {
+			k.Set(key, "sweep_val")
+		}
+	}()
+	n, err := k.Sweep(context.Background())
+	if err != nil || n != 0 {
+		t.Errorf("k2hash.Sweep() = (%v, %v), want 0", n, err)
+	}
+	for _, key := range keys {
+		if _, err := k.Get(key); err != nil {
+			t.Errorf("k2hash.Get(%v) return err %v", key, err)
+		}
+	}
+}
+
+// testSweepBatch tests k2hash.Sweep removes more expired keys than a batch.
+func testSweepBatch(t *testing.T) {
+	k, err := k2hash.NewMemoryK2hash()
+	if err != nil {
+		t.Errorf("k2hash.NewMemoryK2hash() return err %v", err)
+		return
+	}
+	defer k.Close()
+	var expired, alive []string
+	for i := 0; i < 250; i++ {
+		expired = append(expired, fmt.Sprintf("sweep_key%v", i))
+		if i%10 == 0 {
+			alive = append(alive, fmt.Sprintf("sweep_alive%v", i))
+		}
+	}
+	testSweepArgs(k, expired, alive, t)
+	n, err := k.Sweep(context.Background())
+	if err != nil || n != len(expired) {
+		t.Errorf("k2hash.Sweep() = (%v, %v), want %v", n, err, len(expired))
+	}
+	count := 0
+	for range k.Keys() {
+		count++
+	}
+	if count != len(alive) {
+		t.Errorf("k2hash.Keys() walks %v keys, want %v", count, len(alive))
+	}
+}
+
+// testSweeper tests k2hash.WithSweeper.
+func testSweeper(t *testing.T) {
+	if _, err := k2hash.NewMemoryK2hash(k2hash.WithSweeper(-time.Second)); !errors.Is(err, k2hash.ErrInvalid) {
+		t.Errorf("k2hash.NewMemoryK2hash(WithSweeper(-1s)) return err %v, want ErrInvalid", err)
+	}
+	k, err := k2hash.NewMemoryK2hash(k2hash.WithSweeper(10*time.Millisecond), k2hash.WithSweepRate(1000))
+	if err != nil {
+		t.Errorf("k2hash.NewMemoryK2hash() return err %v", err)
+		return
+	}
+	testSweepArgs(k, []string{"sweep_key1"}, []string{"sweep_key2"}, t)
+	deadline := time.Now().Add(5 * time.Second)
+	for k.SweepStats().Removed < 1 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if stats := k.SweepStats(); stats.Removed != 1 {
+		t.Errorf("k2hash.SweepStats() = %v, want 1 removed", stats)
+	}
+	if ok, err := k.Close(); !ok {
+		t.Errorf("k2hash.Close() return false. want true. err %v", err)
+	}
+}
+
+func testSweepArgs(k *k2hash.K2hash, expired []string, alive []string, t *testing.T) {
+	// ExpireAt removes a key at once with a past time, so that the keys expire after a second.
+	for _, key := range expired {
+		if ok, err := k.Set(key, "sweep_val", k2hash.WithExpire(time.Second)); !ok {
+			t.Errorf("k2hash.Set(%v) return false. want true. err %v", key, err)
+		}
+	}
+	for _, key := range alive {
+		if ok, err := k.Set(key, "sweep_val", k2hash.WithExpire(time.Hour)); !ok {
+			t.Errorf("k2hash.Set(%v) return false. want true. err %v", key, err)
+		}
+	}
+	time.Sleep(1500 * time.Millisecond)
+}
+
+// Local Variables:
+// c-basic-offset: 4
+// tab-width: 4
+// indent-tabs-mode: t
+// End:
+// vim600: noexpandtab sw=4 ts=4 fdm=marker
+// vim<600: noexpandtab sw=4 ts=4
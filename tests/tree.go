@@ -0,0 +1,207 @@
+//
+// k2hash_go
+//
+// Copyright 2018 Yahoo Japan Corporation.
+//
+// Go driver for k2hash that is a NoSQL Key Value Store(KVS) library.
+// For k2hash, see https://github.com/yahoojapan/k2hash for the details.
+//
+// For the full copyright and license information, please view
+// the license file that was distributed with this source code.
+//
+// AUTHOR:   Hirotaka Wakabayashi
+// CREATE:   Sun, 18 Oct 2026
+// REVISION:
+//
+
+package k2hashtest
+
+import (
+	// #cgo CFLAGS: -g -Wall -O2 -Wall -Wextra -Wno-unused-variable -Wno-unused-parameter -I. -I/usr/include/k2hash
+	// #cgo LDFLAGS: -L/usr/lib -lk2hash
+	// #include <stdlib.h>
+	// #include "k2hash.h"
+	"C"
+)
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/yahoojapan/k2hash_go/k2hash"
+)
+
+// The actual test functions are in non-_test.go files
+// so that they can use cgo (import "C").
+// These wrappers are here for gotest to find.
+
+// setupTree saves tree/a -> tree/a/b, tree/shared; tree/a/b -> tree/a/b/c, and an attribute of tree/a/b.
+func setupTree(k *k2hash.K2hash, t *testing.T) {
+	for _, key := range []string{"tree/a", "tree/a/b", "tree/a/b/c", "tree/shared", "tree/parent"} {
+		if ok, err := k.Set(key, key+"_val"); !ok {
+			t.Errorf("k2hash.Set(%v) return false. want true. err %v", key, err)
+		}
+	}
+	if ok, err := k.SetSubKeys("tree/a", []string{"tree/a/b", "tree/shared"}); !ok {
+		t.Errorf("k2hash.SetSubKeys(tree/a) return false. want true. err %v", err)
+	}
+	if ok, err := k.SetSubKeys("tree/a/b", []string{"tree/a/b/c"}); !ok {
+		t.Errorf("k2hash.SetSubKeys(tree/a/b) return false. want true. err %v", err)
+	}
+	if ok, err := k.SetSubKeys("tree/parent", []string{"tree/a"}); !ok {
+		t.Errorf("k2hash.SetSubKeys(tree/parent) return false. want true. err %v", err)
+	}
+	if ok, err := k.AddAttr("tree/a/b", "tree_attr", "tree_attr_val"); !ok {
+		t.Errorf("k2hash.AddAttr(tree/a/b) return false. want true. err %v", err)
+	}
+}
+
+// testCopyTree tests k2hash.CopyTree.
+func testCopyTree(t *testing.T) {
+	f := "/tmp/test.k2h"
+	k, err := k2hash.NewK2hash(f, k2hash.WithRemoveFile(true))
+	if err != nil {
+		t.Errorf("k2hash.NewK2hash(%v) return err %v", f, err)
+		return
+	}
+	defer k.Close()
+	setupTree(k, t)
+	prefix := k2hash.WithTreePrefixNaming("/")
+	// 1. dry run writes nothing
+	changes, err := k.CopyTree("tree/a", "tree/x", prefix, k2hash.WithTreeDryRun(), k2hash.WithTreeParent("tree/parent"))
+	if err != nil || len(changes) != 4 || changes[3].Op != k2hash.TreeLink {
+		t.Errorf("k2hash.CopyTree(dry run) = (%v, %v), want 3 copies and a link", changes, err)
+	}
+	if _, err := k.Get("tree/x"); !errors.Is(err, k2hash.ErrNotFound) {
+		t.Errorf("k2hash.Get(tree/x) after dry run return err %v, want ErrNotFound", err)
+	}
+	// 2. copy
+	if _, err := k.CopyTree("tree/a", "tree/x", prefix, k2hash.WithTreeParent("tree/parent")); err != nil {
+		t.Errorf("k2hash.CopyTree(tree/a, tree/x) return err %v", err)
+	}
+	testTreeCopied(k, "tree/x", t)
+	for _, key := range []string{"tree/a", "tree/a/b", "tree/a/b/c"} {
+		if val, err := k.Get(key); err != nil || val.String() != key+"_val" {
+			t.Errorf("k2hash.Get(%v) = (%v, %v), want the source kept", key, val, err)
+		}
+	}
+	if skeys, err := k.GetSubKeys("tree/parent"); err != nil || !reflect.DeepEqual(skeys, []string{"tree/a", "tree/x"}) {
+		t.Errorf("k2hash.GetSubKeys(tree/parent) = (%v, %v), want [tree/a tree/x]", skeys, err)
+	}
+	// 3. errors
+	if _, err := k.CopyTree("tree/a", "tree/x", prefix); !errors.Is(err, k2hash.ErrExist) {
+		t.Errorf("k2hash.CopyTree(tree/a, tree/x) again return err %v, want ErrExist", err)
+	}
+	if _, err := k.CopyTree("tree/none", "tree/y"); !errors.Is(err, k2hash.ErrNotFound) {
+		t.Errorf("k2hash.CopyTree(tree/none) return err %v, want ErrNotFound", err)
+	}
+	if _, err := k.CopyTree("tree/a", "tree/y", k2hash.WithTreeOldParent("tree/parent")); !errors.Is(err, k2hash.ErrInvalid) {
+		t.Errorf("k2hash.CopyTree(WithTreeOldParent) return err %v, want ErrInvalid", err)
+	}
+	if _, err := k.CopyTree("tree/a", "tree/y", k2hash.WithTreePrefixNaming("")); !errors.Is(err, k2hash.ErrInvalid) {
+		t.Errorf("k2hash.CopyTree(WithTreePrefixNaming(\"\")) return err %v, want ErrInvalid", err)
+	}
+	// 4. a subkey which only begins with the name of the source is shared by prefix naming
+	for _, key := range []string{"tree/p", "tree/p/q", "tree/pq"} {
+		if ok, err := k.Set(key, key+"_val"); !ok {
+			t.Errorf("k2hash.Set(%v) return false. want true. err %v", key, err)
+		}
+	}
+	if ok, err := k.SetSubKeys("tree/p", []string{"tree/p/q", "tree/pq"}); !ok {
+		t.Errorf("k2hash.SetSubKeys(tree/p) return false. want true. err %v", err)
+	}
+	if changes, err := k.CopyTree("tree/p", "tree/z", prefix); err != nil || len(changes) != 2 {
+		t.Errorf("k2hash.CopyTree(tree/p, tree/z) = (%v, %v), want 2 copies", changes, err)
+	}
+	skeys, err := k.GetSubKeys("tree/z")
+	sort.Strings(skeys)
+	if want := []string{"tree/pq", "tree/z/q"}; err != nil || !reflect.DeepEqual(skeys, want) {
+		t.Errorf("k2hash.GetSubKeys(tree/z) = (%v, %v), want %v", skeys, err, want)
+	}
+	if _, err := k.Get("tree/zq"); !errors.Is(err, k2hash.ErrNotFound) {
+		t.Errorf("k2hash.Get(tree/zq) return err %v, want ErrNotFound", err)
+	}
+	// 5. all subkeys are copied by default
+	if changes, err := k.CopyTree("tree/a", "tree/d"); err != nil || len(changes) != 4 {
+		t.Errorf("k2hash.CopyTree(tree/a, tree/d) = (%v, %v), want 4 copies", changes, err)
+	}
+	for _, key := range []string{"tree/a/b", "tree/a/b/c", "tree/shared"} {
+		if val, err := k.Get("tree/d/" + key); err != nil || val.String() != key+"_val" {
+			t.Errorf("k2hash.Get(tree/d/%v) = (%v, %v), want %v_val", key, val, err, key)
+		}
+	}
+	skeys, err = k.GetSubKeys("tree/d")
+	sort.Strings(skeys)
+	if want := []string{"tree/d/tree/a/b", "tree/d/tree/shared"}; err != nil || !reflect.DeepEqual(skeys, want) {
+		t.Errorf("k2hash.GetSubKeys(tree/d) = (%v, %v), want %v", skeys, err, want)
+	}
+}
+
+// testMoveTree tests k2hash.MoveTree.
+func testMoveTree(t *testing.T) {
+	f := "/tmp/test.k2h"
+	k, err := k2hash.NewK2hash(f, k2hash.WithRemoveFile(true))
+	if err != nil {
+		t.Errorf("k2hash.NewK2hash(%v) return err %v", f, err)
+		return
+	}
+	defer k.Close()
+	setupTree(k, t)
+	if ok, err := k.Set("tree/newparent", "tree/newparent_val"); !ok {
+		t.Errorf("k2hash.Set(tree/newparent) return false. want true. err %v", err)
+	}
+	changes, err := k.MoveTree("tree/a", "tree/m", k2hash.WithTreePrefixNaming("/"),
+		k2hash.WithTreeParent("tree/newparent"), k2hash.WithTreeOldParent("tree/parent"))
+	var ops []string
+	for _, c := range changes {
+		ops = append(ops, c.Op.String())
+	}
+	if want := []string{"copy", "copy", "copy", "link", "remove", "remove", "remove", "unlink"}; err != nil || !reflect.DeepEqual(ops, want) {
+		t.Errorf("k2hash.MoveTree(tree/a, tree/m) = (%v, %v), want %v", ops, err, want)
+	}
+	testTreeCopied(k, "tree/m", t)
+	for _, key := range []string{"tree/a", "tree/a/b", "tree/a/b/c"} {
+		if _, err := k.Get(key); !errors.Is(err, k2hash.ErrNotFound) {
+			t.Errorf("k2hash.Get(%v) return err %v, want ErrNotFound", key, err)
+		}
+	}
+	if val, err := k.Get("tree/shared"); err != nil || val.String() != "tree/shared_val" {
+		t.Errorf("k2hash.Get(tree/shared) = (%v, %v), want the shared key kept", val, err)
+	}
+	if skeys, err := k.GetSubKeys("tree/newparent"); err != nil || !reflect.DeepEqual(skeys, []string{"tree/m"}) {
+		t.Errorf("k2hash.GetSubKeys(tree/newparent) = (%v, %v), want [tree/m]", skeys, err)
+	}
+	if skeys, err := k.GetSubKeys("tree/parent"); err != nil || len(skeys) != 0 {
+		t.Errorf("k2hash.GetSubKeys(tree/parent) = (%v, %v), want no subkeys", skeys, err)
+	}
+}
+
+// testTreeCopied checks the copy of tree/a named dst.
+func testTreeCopied(k *k2hash.K2hash, dst string, t *testing.T) {
+	for _, suffix := range []string{"", "/b", "/b/c"} {
+		if val, err := k.Get(dst + suffix); err != nil || val.String() != "tree/a"+suffix+"_val" {
+			t.Errorf("k2hash.Get(%v) = (%v, %v), want tree/a%v_val", dst+suffix, val, err, suffix)
+		}
+	}
+	skeys, err := k.GetSubKeys(dst)
+	sort.Strings(skeys)
+	if want := []string{dst + "/b", "tree/shared"}; err != nil || !reflect.DeepEqual(skeys, want) {
+		t.Errorf("k2hash.GetSubKeys(%v) = (%v, %v), want %v", dst, skeys, err, want)
+	}
+	if skeys, err := k.GetSubKeys(dst + "/b"); err != nil || !reflect.DeepEqual(skeys, []string{dst + "/b/c"}) {
+		t.Errorf("k2hash.GetSubKeys(%v/b) = (%v, %v), want [%v/b/c]", dst, skeys, err, dst)
+	}
+	if val, err := k.GetAttr(dst+"/b", "tree_attr"); err != nil || string(val) != "tree_attr_val\x00" {
+		t.Errorf("k2hash.GetAttr(%v/b, tree_attr) = (%q, %v), want tree_attr_val", dst, val, err)
+	}
+}
+
+// Local Variables:
+// c-basic-offset: 4
+// tab-width: 4
+// indent-tabs-mode: t
+// End:
+// vim600: noexpandtab sw=4 ts=4 fdm=marker
+// vim<600: noexpandtab sw=4 ts=4
@@ -0,0 +1,139 @@
+//
+// k2hash_go
+//
+// Copyright 2018 Yahoo Japan Corporation.
+//
+// Go driver for k2hash that is a NoSQL Key Value Store(KVS) library.
+// For k2hash, see https://github.com/yahoojapan/k2hash for the details.
+//
+// For the full copyright and license information, please view
+// the license file that was distributed with this source code.
+//
+// AUTHOR:   Hirotaka Wakabayashi
+// CREATE:   Sun, 18 Oct 2026
+// REVISION:
+//
+
+package k2hashtest
+
+import (
+	// #cgo CFLAGS: -g -Wall -O2 -Wall -Wextra -Wno-unused-variable -Wno-unused-parameter -I. -I/usr/include/k2hash
+	// #cgo LDFLAGS: -L/usr/lib -lk2hash
+	// #include <stdlib.h>
+	// #include "k2hash.h"
+	"C"
+)
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yahoojapan/k2hash_go/k2hash"
+)
+
+// The actual test functions are in non-_test.go files
+// so that they can use cgo (import "C").
+// These wrappers are here for gotest to find.
+
+// testTTL tests k2hash.TTL, k2hash.ExpireAt, k2hash.Touch and k2hash.Persist.
+func testTTL(t *testing.T) {
+	f := "/tmp/test.k2h"
+	k, err := k2hash.NewK2hash(f, k2hash.WithRemoveFile(true))
+	if err != nil {
+		t.Errorf("k2hash.NewK2hash(%v) return err %v", f, err)
+		return
+	}
+	defer k.Close()
+	// 1. no expiration
+	if ok, err := k.Set("ttl_key", "ttl_val"); !ok {
+		t.Errorf("k2hash.Set(ttl_key) return false. want true. err %v", err)
+	}
+	if ttl, err := k.TTL("ttl_key"); err != nil || ttl != k2hash.NoExpiration {
+		t.Errorf("k2hash.TTL(ttl_key) = (%v, %v), want NoExpiration", ttl, err)
+	}
+	// 2. expiration by Set
+	if ok, err := k.Set("ttl_key", "ttl_val", k2hash.WithExpire(time.Hour)); !ok {
+		t.Errorf("k2hash.Set(ttl_key) return false. want true. err %v", err)
+	}
+	testTTLArgs(k, time.Hour, t)
+	// 3. ExpireAt keeps the other attributes
+	if ok, err := k.SetAttr("ttl_key", "ttl_attr", "ttl_attr_val"); !ok {
+		t.Errorf("k2hash.SetAttr(ttl_key) return false. want true. err %v", err)
+	}
+	if ok, err := k.ExpireAt("ttl_key", time.Now().Add(2*time.Hour)); !ok {
+		t.Errorf("k2hash.ExpireAt(ttl_key) return false. want true. err %v", err)
+	}
+	testTTLArgs(k, 2*time.Hour, t)
+	if val, err := k.GetAttr("ttl_key", "ttl_attr"); err != nil || string(val) != "ttl_attr_val\x00" {
+		t.Errorf("k2hash.GetAttr(ttl_key, ttl_attr) = (%q, %v), want ttl_attr_val", val, err)
+	}
+	// 4. Touch
+	if ok, err := k.Touch("ttl_key", 30*time.Minute); !ok {
+		t.Errorf("k2hash.Touch(ttl_key) return false. want true. err %v", err)
+	}
+	testTTLArgs(k, 30*time.Minute, t)
+	// 5. Persist
+	if ok, err := k.Persist("ttl_key"); !ok {
+		t.Errorf("k2hash.Persist(ttl_key) return false. want true. err %v", err)
+	}
+	if ttl, err := k.TTL("ttl_key"); err != nil || ttl != k2hash.NoExpiration {
+		t.Errorf("k2hash.TTL(ttl_key) = (%v, %v), want NoExpiration", ttl, err)
+	}
+	if val, err := k.Get("ttl_key"); err != nil || val.String() != "ttl_val" {
+		t.Errorf("k2hash.Get(ttl_key) = (%v, %v), want ttl_val", val, err)
+	}
+	if ok, err := k.Persist("ttl_key"); !ok {
+		t.Errorf("k2hash.Persist(ttl_key) return false. want true. err %v", err)
+	}
+	// 6. invalid arguments
+	if _, err := k.Touch("ttl_key", 0); !errors.Is(err, k2hash.ErrInvalid) {
+		t.Errorf("k2hash.Touch(ttl_key, 0) return err %v, want ErrInvalid", err)
+	}
+	if _, err := k.ExpireAt("ttl_key", time.Time{}); !errors.Is(err, k2hash.ErrInvalid) {
+		t.Errorf("k2hash.ExpireAt(ttl_key, zero) return err %v, want ErrInvalid", err)
+	}
+	// 7. a past time removes the key
+	if ok, err := k.ExpireAt("ttl_key", time.Now().Add(-time.Second)); !ok {
+		t.Errorf("k2hash.ExpireAt(ttl_key) with a past time return false. want true. err %v", err)
+	}
+	if val, err := k.Get("ttl_key"); !errors.Is(err, k2hash.ErrNotFound) {
+		t.Errorf("k2hash.Get(ttl_key) = (%v, %v), want ErrNotFound", val, err)
+	}
+	// 8. no such key
+	if _, err := k.TTL("ttl_nokey"); !errors.Is(err, k2hash.ErrNotFound) {
+		t.Errorf("k2hash.TTL(ttl_nokey) return err %v, want ErrNotFound", err)
+	}
+	if _, err := k.Touch("ttl_nokey", time.Hour); !errors.Is(err, k2hash.ErrNotFound) {
+		t.Errorf("k2hash.Touch(ttl_nokey) return err %v, want ErrNotFound", err)
+	}
+	if _, err := k.Persist("ttl_nokey"); !errors.Is(err, k2hash.ErrNotFound) {
+		t.Errorf("k2hash.Persist(ttl_nokey) return err %v, want ErrNotFound", err)
+	}
+	// 9. a default expiration would be applied again by the rewrite
+	if ok, err := k.Set("ttl_key", "ttl_val", k2hash.WithExpire(time.Hour)); !ok {
+		t.Errorf("k2hash.Set(ttl_key) return false. want true. err %v", err)
+	}
+	if ok, err := k.SetExpirationDuration(3600); !ok {
+		t.Errorf("k2hash.SetExpirationDuration(3600) return false. want true. err %v", err)
+	}
+	if _, err := k.Persist("ttl_key"); !errors.Is(err, k2hash.ErrInvalid) {
+		t.Errorf("k2hash.Persist(ttl_key) with a default expiration return err %v, want ErrInvalid", err)
+	}
+	testTTLArgs(k, time.Hour, t)
+}
+
+func testTTLArgs(k *k2hash.K2hash, want time.Duration, t *testing.T) {
+	ttl, err := k.TTL("ttl_key")
+	if err != nil || ttl <= want-time.Minute || ttl > want+time.Second {
+		t.Errorf("k2hash.TTL(ttl_key) = (%v, %v), want about %v", ttl, err, want)
+	}
+}
+
+// Local Variables:
+// c-basic-offset: 4
+// tab-width: 4
+// indent-tabs-mode: t
+// End:
+// vim600: noexpandtab sw=4 ts=4 fdm=marker
+// vim<600: noexpandtab sw=4 ts=4
@@ -0,0 +1,169 @@
+//
+// k2hash_go
+//
+// Copyright 2018 Yahoo Japan Corporation.
+//
+// Go driver for k2hash that is a NoSQL Key Value Store(KVS) library.
+// For k2hash, see https://github.com/yahoojapan/k2hash for the details.
+//
+// For the full copyright and license information, please view
+// the license file that was distributed with this source code.
+//
+// AUTHOR:   Hirotaka Wakabayashi
+// CREATE:   Sun, 18 Oct 2026
+// REVISION:
+//
+
+package k2hashtest
+
+import (
+	// #cgo CFLAGS: -g -Wall -O2 -Wall -Wextra -Wno-unused-variable -Wno-unused-parameter -I. -I/usr/include/k2hash
+	// #cgo LDFLAGS: -L/usr/lib -lk2hash
+	// #include <stdlib.h>
+	// #include "k2hash.h"
+	"C"
+)
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/yahoojapan/k2hash_go/k2hash"
+)
+
+// The actual test functions are in non-_test.go files
+// so that they can use cgo (import "C").
+// These wrappers are here for gotest to find.
+
+// testWalkSubKeys tests k2hash.WalkSubKeys.
+func testWalkSubKeys(t *testing.T) {
+	f := "/tmp/test.k2h"
+	k, err := k2hash.NewK2hash(f, k2hash.WithRemoveFile(true))
+	if err != nil {
+		t.Errorf("k2hash.NewK2hash(%v) return err %v", f, err)
+		return
+	}
+	defer k.Close()
+	// 1. walk_a -> walk_b, walk_c; walk_b -> walk_d; walk_c -> walk_d, walk_e; walk_d -> walk_a
+	// walk_e doesn't exist.
+	for _, key := range []string{"walk_a", "walk_b", "walk_c", "walk_d"} {
+		if ok, err := k.Set(key, key+"_val"); !ok {
+			t.Errorf("k2hash.Set(%v) return false. want true. err %v", key, err)
+		}
+	}
+	tree := map[string][]string{
+		"walk_a": {"walk_b", "walk_c"},
+		"walk_b": {"walk_d"},
+		"walk_c": {"walk_d", "walk_e"},
+		"walk_d": {"walk_a"},
+	}
+	for key, skeys := range tree {
+		if ok, err := k.SetSubKeys(key, skeys); !ok {
+			t.Errorf("k2hash.SetSubKeys(%v) return false. want true. err %v", key, err)
+		}
+	}
+	// 2. modes and depth
+	testWalkSubKeysArgs(k, nil, []string{"walk_a", "walk_b", "walk_d", "walk_c", "walk_e"}, t)
+	testWalkSubKeysArgs(k, []func(*k2hash.WalkParams){k2hash.WithWalkMode(k2hash.WalkBFS)},
+		[]string{"walk_a", "walk_b", "walk_c", "walk_d", "walk_e"}, t)
+	testWalkSubKeysArgs(k, []func(*k2hash.WalkParams){k2hash.WithWalkDepth(1)},
+		[]string{"walk_a", "walk_b", "walk_c"}, t)
+	testWalkSubKeysArgs(k, []func(*k2hash.WalkParams){k2hash.WithWalkDepth(0)},
+		[]string{"walk_a"}, t)
+	// 3. path
+	paths := map[string]string{}
+	err = k.WalkSubKeys("walk_a", func(path [][]byte, key []byte) error {
+		var names []string
+		for _, p := range path {
+			names = append(names, walkKeyName(p))
+		}
+		paths[walkKeyName(key)] = strings.Join(names, "/")
+		// the path of the other keys isn't changed
+		for i := range path {
+			path[i] = nil
+		}
+		return nil
+	})
+	want := map[string]string{"walk_a": "", "walk_b": "walk_a", "walk_d": "walk_a/walk_b", "walk_c": "walk_a", "walk_e": "walk_a/walk_c"}
+	if err != nil || !reflect.DeepEqual(paths, want) {
+		t.Errorf("k2hash.WalkSubKeys(walk_a) paths = (%v, %v), want %v", paths, err, want)
+	}
+	// 4. SkipSubKeys and errors
+	var keys []string
+	err = k.WalkSubKeys("walk_a", func(path [][]byte, key []byte) error {
+		keys = append(keys, walkKeyName(key))
+		if walkKeyName(key) == "walk_b" {
+			return k2hash.SkipSubKeys
+		}
+		return nil
+	})
+	if wantKeys := []string{"walk_a", "walk_b", "walk_c", "walk_d", "walk_e"}; err != nil || !reflect.DeepEqual(keys, wantKeys) {
+		t.Errorf("k2hash.WalkSubKeys(SkipSubKeys) = (%v, %v), want %v", keys, err, wantKeys)
+	}
+	errStop := errors.New("stop")
+	keys = nil
+	err = k.WalkSubKeys("walk_a", func(path [][]byte, key []byte) error {
+		keys = append(keys, walkKeyName(key))
+		return errStop
+	})
+	if !errors.Is(err, errStop) || len(keys) != 1 {
+		t.Errorf("k2hash.WalkSubKeys(error) = (%v, %v), want errStop after walk_a", keys, err)
+	}
+	if err := k.WalkSubKeys("walk_none", func(path [][]byte, key []byte) error { return nil }); !errors.Is(err, k2hash.ErrNotFound) {
+		t.Errorf("k2hash.WalkSubKeys(walk_none) return err %v, want ErrNotFound", err)
+	}
+	// 5. a subkey shared by two branches is visited through the first branch in DFS.
+	// walk_p -> walk_q, walk_r; walk_q -> walk_r, walk_s
+	for key, skeys := range map[string][]string{"walk_p": {"walk_q", "walk_r"}, "walk_q": {"walk_r", "walk_s"}} {
+		if ok, err := k.Set(key, key+"_val"); !ok {
+			t.Errorf("k2hash.Set(%v) return false. want true. err %v", key, err)
+		}
+		if ok, err := k.SetSubKeys(key, skeys); !ok {
+			t.Errorf("k2hash.SetSubKeys(%v) return false. want true. err %v", key, err)
+		}
+	}
+	keys = nil
+	paths = map[string]string{}
+	err = k.WalkSubKeys("walk_p", func(path [][]byte, key []byte) error {
+		var names []string
+		for _, p := range path {
+			names = append(names, walkKeyName(p))
+		}
+		keys = append(keys, walkKeyName(key))
+		paths[walkKeyName(key)] = strings.Join(names, "/")
+		return nil
+	})
+	if wantKeys := []string{"walk_p", "walk_q", "walk_r", "walk_s"}; err != nil || !reflect.DeepEqual(keys, wantKeys) {
+		t.Errorf("k2hash.WalkSubKeys(walk_p) = (%v, %v), want %v", keys, err, wantKeys)
+	}
+	if paths["walk_r"] != "walk_p/walk_q" {
+		t.Errorf("k2hash.WalkSubKeys(walk_p) path of walk_r = %v, want walk_p/walk_q", paths["walk_r"])
+	}
+}
+
+// testWalkSubKeysArgs walks from walk_a with the options and checks the order of keys.
+func testWalkSubKeysArgs(k *k2hash.K2hash, options []func(*k2hash.WalkParams), want []string, t *testing.T) {
+	var keys []string
+	err := k.WalkSubKeys("walk_a", func(path [][]byte, key []byte) error {
+		keys = append(keys, walkKeyName(key))
+		return nil
+	}, options...)
+	if err != nil || !reflect.DeepEqual(keys, want) {
+		t.Errorf("k2hash.WalkSubKeys(walk_a) = (%v, %v), want %v", keys, err, want)
+	}
+}
+
+// walkKeyName returns a key saved as a string without the null termination.
+func walkKeyName(key []byte) string {
+	return strings.TrimSuffix(string(key), "\x00")
+}
+
+// Local Variables:
+// c-basic-offset: 4
+// tab-width: 4
+// indent-tabs-mode: t
+// End:
+// vim600: noexpandtab sw=4 ts=4 fdm=marker
+// vim<600: noexpandtab sw=4 ts=4